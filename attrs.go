@@ -0,0 +1,73 @@
+package crumbs
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// the block ends the text (only the trailing hashtags can follow it)
+	reAttrBlock = regexp.MustCompile(`[ \t]*\{([\pL_][^{}]*)\}((?:[ \t]+#[\pL_][\pL\pN_-]*)*)[ \t]*$`)
+	reAttr      = regexp.MustCompile(`([\pL_][\pL\pN_-]*)(?:=("[^"]*"|[^\s"]+))?`)
+)
+
+// lookForAttrs moves the attributes block at the end of the text
+// (i.e. {color=#e76f51 shape=box bold}) from the note text to the
+// note attributes; the attributes without a value (flags, i.e. 'bold')
+// are set to 'true'. The braces in the middle of the text are left alone.
+func lookForAttrs(note *Entry) {
+	note.text = reAttrBlock.ReplaceAllStringFunc(note.text, func(block string) string {
+		res := reAttrBlock.FindStringSubmatch(block)
+		body, tags := res[1], res[2]
+
+		// every token must be an attribute
+		rest := strings.TrimSpace(reAttr.ReplaceAllString(body, ""))
+		if rest != "" {
+			return block
+		}
+
+		if note.attrs == nil {
+			note.attrs = map[string]string{}
+		}
+		for _, res := range reAttr.FindAllStringSubmatch(body, -1) {
+			val := res[2]
+			if val == "" {
+				val = "true"
+			} else if s, err := strconv.Unquote(val); err == nil {
+				val = s
+			}
+			note.attrs[res[1]] = val
+		}
+
+		return tags
+	})
+}
+
+// formatAttrs returns the attributes block, with the keys sorted.
+func formatAttrs(attrs map[string]string) string {
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		switch v := attrs[k]; {
+		case v == "true":
+			parts = append(parts, k)
+		case strings.ContainsAny(v, " \t\"{}"):
+			parts = append(parts, k+"="+strconv.Quote(v))
+		default:
+			parts = append(parts, k+"="+v)
+		}
+	}
+
+	return "{" + strings.Join(parts, " ") + "}"
+}
@@ -0,0 +1,68 @@
+package crumbs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookForAttrs(t *testing.T) {
+	tests := []struct {
+		text  string
+		want  string
+		attrs map[string]string
+	}{
+		{"Risk {color=#e76f51 shape=box bold}", "Risk", map[string]string{"color": "#e76f51", "shape": "box", "bold": "true"}},
+		{"Risk {fontsize=18} #risk #q3", "Risk #risk #q3", map[string]string{"fontsize": "18"}},
+		{"Template {name} placeholder", "Template {name} placeholder", nil},
+		{"Risk {fontsize=18} {bold}", "Risk {fontsize=18}", map[string]string{"bold": "true"}},
+		{`Risk {label="a b" fillcolor=#ffcdb2}`, "Risk", map[string]string{"label": "a b", "fillcolor": "#ffcdb2"}},
+		{"a {x = y} b", "a {x = y} b", nil},
+		{"no attributes", "no attributes", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			note := newNote(1, tt.text)
+			lookForAttrs(note)
+			assert.Equal(t, tt.want, note.text)
+			assert.Equal(t, tt.attrs, note.attrs)
+		})
+	}
+}
+
+func TestParseAttrs(t *testing.T) {
+	got, err := ParseLines([]string{
+		"* main idea",
+		"** Risk {color=#e76f51 shape=box bold} {#risk}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	risk := got.Childrens()[0].Childrens()[0]
+	assert.Equal(t, "Risk", risk.Text())
+	assert.Equal(t, "risk", risk.Anchor())
+	assert.Equal(t, "#e76f51", risk.Attr("color"))
+	assert.Equal(t, "true", risk.Attr("bold"))
+}
+
+func TestFormatKeepsBraces(t *testing.T) {
+	src := "* Template {name} placeholder\n** Risk {bold} #work\n"
+
+	note, err := ParseLines(strings.SplitAfter(src, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := note.Childrens()[0]
+	assert.Equal(t, "Template {name} placeholder", main.Text())
+	assert.Equal(t, map[string]string{}, main.Attrs())
+
+	var sb strings.Builder
+	if err := Format(&sb, note); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, src, sb.String())
+}
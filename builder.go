@@ -0,0 +1,69 @@
+package crumbs
+
+import (
+	"io"
+	"strings"
+)
+
+// Builder builds an entry tree adding one entry at a time,
+// just like the parser does for each text line.
+// It's meant for the readers of other formats (i.e. OPML).
+type Builder struct {
+	p *parser
+}
+
+// NewBuilder creates a new tree builder.
+func NewBuilder(opts ...ParseOption) (*Builder, error) {
+	p, err := newParser(newParseOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Builder{p: p}, nil
+}
+
+// Limit returns a reader that reads from r until the
+// MaxSize option limit (if any), then fails with ErrTooLarge.
+func (b *Builder) Limit(r io.Reader) io.Reader {
+	return LimitReader(r, b.p.opts.MaxSize)
+}
+
+// Add appends a new entry to the tree at the given level (1 for
+// the main ideas). As in the crumbs syntax, the text can start
+// with an icon marker, the rest of the text is kept as it is
+// (braces, hashtags and arrows are not attributes, tags and links).
+// The line is the entry position in the source, used for the
+// diagnostics (zero if unknown).
+func (b *Builder) Add(line, level int, text string) (*Entry, error) {
+	e := b.p.newEntry(line, 1, level, strings.TrimSpace(text))
+	return b.p.attach(e)
+}
+
+// SetIcon resolves the icon name and assigns it to the entry.
+func (b *Builder) SetIcon(e *Entry, name string) {
+	b.p.setIcon(e, name)
+}
+
+// SetNote sets the (multi-line) note of the entry.
+func (b *Builder) SetNote(e *Entry, note string) {
+	e.note = strings.TrimSpace(note)
+}
+
+// SetAttr sets the value of the named entry attribute;
+// an empty value removes the attribute.
+func (b *Builder) SetAttr(e *Entry, key, value string) {
+	if value == "" {
+		delete(e.attrs, key)
+		return
+	}
+
+	if e.attrs == nil {
+		e.attrs = map[string]string{}
+	}
+	e.attrs[key] = value
+}
+
+// Tree returns the entry tree, or the errors found in strict mode.
+func (b *Builder) Tree() (*Entry, error) {
+	return b.p.result()
+}
@@ -0,0 +1,74 @@
+package crumbs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilder(t *testing.T) {
+	b, err := NewBuilder(ImagesPath("icons"), ImagesSuffix("png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		level int
+		text  string
+		icon  string
+	}{
+		{1, "main idea", "bulb"},
+		{2, "topic 1", ""},
+		{3, "[[map-signs]] sub topic", ""},
+		{2, " topic 2 ", ""},
+	}
+
+	for i, tt := range tests {
+		e, err := b.Add(i+1, tt.level, tt.text)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b.SetIcon(e, tt.icon)
+		b.SetAttr(e, "color", tt.icon)
+	}
+
+	got, err := b.Tree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := got.Childrens()[0]
+	assert.Equal(t, "main idea", main.Text())
+	assert.Equal(t, "icons/bulb.png", main.Icon())
+	assert.Equal(t, []string{"topic 1", "topic 2"}, []string{main.Childrens()[0].Text(), main.Childrens()[1].Text()})
+	assert.Equal(t, "icons/map-signs.png", main.Childrens()[0].Childrens()[0].Icon())
+	assert.Equal(t, "n1.2", main.Childrens()[1].ID())
+	assert.Equal(t, "bulb", main.Attr("color"))
+	assert.Equal(t, map[string]string{"color": "bulb"}, main.Attrs())
+	assert.Equal(t, map[string]string{}, main.Childrens()[0].Attrs())
+}
+
+func TestBuilderStrict(t *testing.T) {
+	b, err := NewBuilder(Strict(true), Icons(IconResolverFunc(func(name string) (string, error) {
+		return "", fmt.Errorf("icon '%s' not found", name)
+	})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e, _ := b.Add(1, 1, "main idea")
+	b.SetIcon(e, "bulb")
+	b.Add(2, 3, "too deep")
+
+	_, err = b.Tree()
+	errs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("got [%v] want ParseErrors", err)
+	}
+
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, UnknownIcon, errs[0].Kind)
+	assert.Equal(t, LevelJump, errs[1].Kind)
+	assert.Equal(t, 2, errs[1].Line)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// runFmt implements the 'fmt' subcommand: it rewrites the
+// crumbs source files in the canonical syntax (just like gofmt).
+func runFmt(args []string) error {
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := fs.Bool("w", false, "write result to (source) file instead of stdout")
+	diff := fs.Bool("d", false, "display diffs instead of rewriting files")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "USAGE:\n\n  %s fmt [flags] [path ...]\n\nFLAGS:\n\n", appName())
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		if *write {
+			return fmt.Errorf("cannot use -w with standard input")
+		}
+		src, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		return formatSource("<stdin>", src, false, *diff)
+	}
+
+	for _, name := range fs.Args() {
+		src, err := ioutil.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := formatSource(name, src, *write, *diff); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// formatSource formats the source and writes the result
+// to the standard output, to the named file (write) or
+// as a unified diff against the source (diff).
+//
+// Like gofmt, nothing is written if the source has lines
+// that are not entries (they would be lost).
+func formatSource(name string, src []byte, write, diff bool) error {
+	// keep the icon names as they are
+	keep := crumbs.IconResolverFunc(func(icon string) (string, error) {
+		return icon, nil
+	})
+
+	orphans := 0
+	note, err := crumbs.Parse(bytes.NewReader(src), crumbs.Icons(keep), crumbs.Warn(func(e *crumbs.ParseError) {
+		fmt.Fprintf(os.Stderr, "%s:%d: %s\n", name, e.Line, e.Msg)
+		if e.Kind == crumbs.OrphanText {
+			orphans++
+		}
+	}))
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	if orphans > 0 {
+		return fmt.Errorf("%s: not formatted, the orphan text would be lost", name)
+	}
+
+	var buf bytes.Buffer
+	if err := crumbs.Format(&buf, note); err != nil {
+		return err
+	}
+	res := buf.Bytes()
+
+	if diff {
+		if bytes.Equal(src, res) {
+			return nil
+		}
+
+		str, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        splitLines(src),
+			B:        splitLines(res),
+			FromFile: name + ".orig",
+			ToFile:   name,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Print(str)
+	}
+
+	if write {
+		if bytes.Equal(src, res) {
+			return nil
+		}
+
+		fi, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(name, res, fi.Mode().Perm())
+	}
+
+	if !diff {
+		_, err = os.Stdout.Write(res)
+	}
+	return err
+}
+
+// splitLines splits the text in lines, keeping the line endings.
+func splitLines(src []byte) []string {
+	res := strings.SplitAfter(string(src), "\n")
+	if n := len(res); n > 0 && res[n-1] == "" {
+		res = res[:n-1]
+	}
+	return res
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatSourceOrphans(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crumbs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"orphan.txt", "* main idea\nsome orphan text\n** topic\n"},
+		{"notes.md", "# Title\n- item\n  - sub\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(dir, tt.name)
+			if err := ioutil.WriteFile(name, []byte(tt.src), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := formatSource(name, []byte(tt.src), true, false)
+			assert.EqualError(t, err, name+": not formatted, the orphan text would be lost")
+
+			got, err := ioutil.ReadFile(name)
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.src, string(got))
+			}
+		})
+	}
+}
+
+func TestFormatSourceWrite(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crumbs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	name := filepath.Join(dir, "notes.txt")
+	src := "*   main idea\n*** topic\n"
+	if err := ioutil.WriteFile(name, []byte(src), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := formatSource(name, []byte(src), true, false); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ioutil.ReadFile(name)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "* main idea\n** topic\n", string(got))
+	}
+}
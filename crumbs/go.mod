@@ -2,6 +2,11 @@ module github.com/lucasepe/crumbs/crumbs
 
 go 1.14
 
-require github.com/lucasepe/crumbs v0.3.0
+require (
+	github.com/lucasepe/crumbs v0.3.0
+	github.com/pmezard/go-difflib v1.0.0
+	github.com/stretchr/testify v1.6.1
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 replace github.com/lucasepe/crumbs => ../
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
@@ -10,12 +11,17 @@ import (
 	"strings"
 
 	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/freemind"
 	"github.com/lucasepe/crumbs/gv"
+	"github.com/lucasepe/crumbs/mermaid"
+	"github.com/lucasepe/crumbs/opml"
+	"github.com/lucasepe/crumbs/plantuml"
+	"github.com/lucasepe/crumbs/svg"
+	"gopkg.in/yaml.v3"
 )
 
 const (
-	maxFileSize int64 = 512 * 1000 // 512 Kb
-	banner            = `
+	banner = `
     __  ____  __ __  ___ ___  ____    _____
    /  ]|    \|  |  ||   |   ||    \  / ___/ v{{VERSION}}
   /  / |  D  )  |  || _   _ ||  o  )(   \_ 
@@ -33,59 +39,251 @@ var (
 	flagWrapLim    uint
 	flagImagesPath string
 	flagImagesType string
+	flagCheckIcons bool
+	flagEmbedIcons bool
+	flagIDs        crumbs.IDStrategy
+	flagStrict     bool
+	flagMaxSize    int64
+	flagFormat     string
+	flagLayout     string
+	flagFrom       string
+	flagTag        string
+	flagRoot       string
+	flagMaxDepth   int
+	flagMore       bool
+	flagTheme      string
+	flagColoring   gv.Coloring
+	flagOutput     string
+	flagDotPath    string
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "fmt" {
+		exitOnErr(runFmt(os.Args[2:]))
+		return
+	}
+
 	configureFlags()
 
 	entry, err := readEntry()
+	if errs, ok := err.(crumbs.ParseErrors); ok {
+		for _, el := range errs {
+			printDiagnostic(el)
+		}
+		os.Exit(1)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
 		os.Exit(1)
 	}
 
-	cfg := gv.RenderConfig{
-		WrapTextLimit:  flagWrapLim,
-		VerticalLayout: flagVertical,
+	if flagTag != "" {
+		entry = crumbs.Filter(entry, hasAnyTag(strings.Split(flagTag, ",")))
+	}
+
+	if flagRoot != "" {
+		el := entry.FindByID(flagRoot)
+		if el == nil {
+			el = entry.FindByPath(flagRoot)
+		}
+		if el == nil {
+			fmt.Fprintf(os.Stderr, "error: entry '%s' not found\n", flagRoot)
+			os.Exit(1)
+		}
+		entry = el.Subtree()
+	}
+
+	if flagMaxDepth > 0 {
+		entry = entry.Prune(flagMaxDepth, flagMore)
+	}
+
+	if flagOutput != "" {
+		err = writeOutput(flagOutput, entry)
+	} else if err = checkEmbedIcons(flagFormat); err == nil {
+		err = render(os.Stdout, entry, strings.ToLower(flagFormat))
 	}
-	if err = gv.Render(os.Stdout, entry, cfg); err != nil {
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %s\n", err.Error())
 		os.Exit(1)
 	}
 }
 
-func readInput() ([]byte, error) {
-	limit := maxFileSize
-	args := flag.Args()
-	if len(args) == 0 {
-		return readFileObject(os.Stdin, limit)
+// render writes the entry tree using the output format.
+func render(wr io.Writer, entry *crumbs.Entry, format string) error {
+	vertical, radial := flagVertical, false
+	switch strings.ToLower(flagLayout) {
+	case "", "horizontal":
+	case "vertical":
+		vertical = true
+	case "radial":
+		radial = true
+	default:
+		return fmt.Errorf("unknown layout '%s'", flagLayout)
+	}
+
+	switch format {
+	case "dot":
+		th, err := loadTheme(flagTheme)
+		if err != nil {
+			return err
+		}
+		return gv.Render(wr, entry, gv.RenderConfig{
+			WrapTextLimit:  flagWrapLim,
+			VerticalLayout: vertical,
+			RadialLayout:   radial,
+			Theme:          th,
+			Coloring:       flagColoring,
+		})
+	case "svg":
+		return svg.Render(wr, entry, svg.RenderConfig{
+			WrapTextLimit:  flagWrapLim,
+			VerticalLayout: vertical,
+			RadialLayout:   radial,
+		})
+	case "mermaid":
+		return mermaid.Render(wr, entry, mermaid.RenderConfig{})
+	case "opml":
+		return opml.Write(wr, entry)
+	case "mm":
+		return freemind.Write(wr, entry)
+	case "json":
+		enc := json.NewEncoder(wr)
+		enc.SetIndent("", "  ")
+		return enc.Encode(entry)
+	case "yaml":
+		enc := yaml.NewEncoder(wr)
+		enc.SetIndent(2)
+		if err := enc.Encode(entry); err != nil {
+			return err
+		}
+		return enc.Close()
+	case "plantuml":
+		return plantuml.Render(wr, entry, plantuml.RenderConfig{
+			BalancedSides: radial,
+		})
+	default:
+		return fmt.Errorf("unknown output format '%s'", format)
 	}
-	return readFile(args[0], limit)
 }
 
 func readEntry() (*crumbs.Entry, error) {
-	src, err := readInput()
+	r := os.Stdin
+	if args := flag.Args(); len(args) > 0 {
+		f, err := os.Open(args[0])
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	opts := []crumbs.ParseOption{
+		crumbs.ImagesPath(flagImagesPath),
+		crumbs.ImagesSuffix(flagImagesType),
+		crumbs.CheckIcons(flagCheckIcons),
+		crumbs.IDs(flagIDs),
+		crumbs.Strict(flagStrict),
+		crumbs.Warn(printDiagnostic),
+		crumbs.MaxSize(flagMaxSize),
+	}
+
+	switch from := inputFormat(); from {
+	case "crumbs":
+		return crumbs.Parse(r, opts...)
+	case "markdown":
+		return crumbs.Parse(r, append(opts, crumbs.UseSyntax(crumbs.MarkdownSyntax))...)
+	case "indent":
+		return crumbs.Parse(r, append(opts, crumbs.UseSyntax(crumbs.IndentSyntax))...)
+	case "org":
+		return crumbs.Parse(r, append(opts, crumbs.UseSyntax(crumbs.OrgSyntax))...)
+	case "opml":
+		return opml.Read(r, opts...)
+	case "mm":
+		return freemind.Read(r, opts...)
+	case "json":
+		return decodeEntry(json.NewDecoder(crumbs.LimitReader(r, flagMaxSize)))
+	case "yaml":
+		return decodeEntry(yaml.NewDecoder(crumbs.LimitReader(r, flagMaxSize)))
+	default:
+		return nil, fmt.Errorf("unknown input format '%s'", from)
+	}
+}
+
+// loadTheme returns the built-in theme with the
+// given name or the one defined in the JSON file.
+func loadTheme(name string) (*gv.Theme, error) {
+	if !strings.EqualFold(filepath.Ext(name), ".json") {
+		return gv.ThemeByName(name)
+	}
+
+	f, err := os.Open(name)
 	if err != nil {
 		return nil, err
 	}
-	text := string(src)
-	lines := strings.SplitAfter(text, "\n")
-	return crumbs.ParseLines(lines, flagImagesPath, flagImagesType)
+	defer f.Close()
+
+	return gv.LoadTheme(f)
 }
 
-func readFileObject(r io.Reader, limit int64) ([]byte, error) {
-	lr := io.LimitReader(r, limit)
-	return ioutil.ReadAll(lr)
+// hasAnyTag returns a function that reports
+// whether an entry has at least one of the tags.
+func hasAnyTag(tags []string) func(*crumbs.Entry) bool {
+	return func(e *crumbs.Entry) bool {
+		for _, tag := range tags {
+			if e.HasTag(strings.TrimPrefix(strings.TrimSpace(tag), "#")) {
+				return true
+			}
+		}
+		return false
+	}
 }
 
-func readFile(name string, limit int64) ([]byte, error) {
-	r, err := os.Open(name)
-	if err != nil {
+// decodeEntry decodes the entry tree using a JSON or YAML decoder.
+func decodeEntry(dec interface{ Decode(v interface{}) error }) (*crumbs.Entry, error) {
+	res := new(crumbs.Entry)
+	if err := dec.Decode(res); err != nil {
 		return nil, err
 	}
-	defer r.Close()
+	return res, nil
+}
+
+// inputFormat returns the input format: the one specified
+// with the '-from' flag or the one guessed by the file extension.
+func inputFormat() string {
+	if flagFrom != "" {
+		return strings.ToLower(flagFrom)
+	}
 
-	return readFileObject(r, limit)
+	switch strings.ToLower(filepath.Ext(inputName())) {
+	case ".md", ".markdown":
+		return "markdown"
+	case ".org":
+		return "org"
+	case ".opml":
+		return "opml"
+	case ".mm":
+		return "mm"
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return "crumbs"
+	}
+}
+
+// inputName returns the name of the input source.
+func inputName() string {
+	if args := flag.Args(); len(args) > 0 {
+		return args[0]
+	}
+	return "<stdin>"
+}
+
+// printDiagnostic prints a parse diagnostic
+// as 'file:line: message' so that editors can jump to it.
+func printDiagnostic(e *crumbs.ParseError) {
+	fmt.Fprintf(os.Stderr, "%s:%d: %s\n", inputName(), e.Line, e.Msg)
 }
 
 func configureFlags() {
@@ -100,7 +298,11 @@ func configureFlags() {
 
 		fmt.Print("EXAMPLE(s):\n\n")
 		fmt.Printf("  %s agenda.txt | dot -Tpng > output.png\n", name)
-		fmt.Printf("  cat agenda.txt | %s | dot -Tpng > output.png\n\n", name)
+		fmt.Printf("  cat agenda.txt | %s | dot -Tpng > output.png\n", name)
+		fmt.Printf("  %s -o output.png agenda.txt (runs Graphviz for you)\n", name)
+		fmt.Printf("  %s -format svg agenda.txt > output.svg\n", name)
+		fmt.Printf("  %s -layout radial agenda.txt | twopi -Tpng > output.png\n", name)
+		fmt.Printf("  %s fmt -w agenda.txt (rewrites the file in the canonical syntax)\n\n", name)
 
 		fmt.Print("FLAGS:\n\n")
 		flag.CommandLine.SetOutput(os.Stdout)
@@ -114,11 +316,35 @@ func configureFlags() {
 	flag.CommandLine.Init(os.Args[0], flag.ExitOnError)
 
 	flag.CommandLine.BoolVar(&flagVertical, "vertical", false,
-		"layout entries as vertical directed graph")
+		"layout entries as vertical directed graph (same as -layout vertical)")
+	flag.CommandLine.StringVar(&flagLayout, "layout", "horizontal",
+		"entries layout [horizontal,vertical,radial]")
 	flag.CommandLine.UintVar(&flagWrapLim, "lim", 28, "wraps each line within this width in characters")
+	flag.CommandLine.StringVar(&flagFormat, "format", "dot", "output format [dot,svg,mermaid,plantuml,opml,mm,json,yaml]")
+	flag.CommandLine.StringVar(&flagOutput, "o", "",
+		"write the output to this file, drawing png, pdf and svg files with Graphviz (format inferred by extension [png,pdf,svg,dot])")
+	flag.CommandLine.StringVar(&flagDotPath, "dot-path", "",
+		"path of the Graphviz dot binary (default $GRAPHVIZ_DOT, then looked for in PATH)")
+	flag.CommandLine.StringVar(&flagFrom, "from", "", "input format [crumbs,markdown,indent,org,opml,mm,json,yaml] (default guessed by file extension)")
+
+	flag.CommandLine.StringVar(&flagTheme, "theme", "light",
+		fmt.Sprintf("dot output theme [%s] or a JSON theme file", strings.Join(gv.ThemeNames(), ",")))
+	flag.CommandLine.Var(&flagColoring, "coloring", "dot output edges coloring [by-level,by-branch,by-tag,none]")
+	flag.CommandLine.StringVar(&flagTag, "tag", "",
+		"keep only the entries with one of these (comma separated) tags and their ancestors")
+
+	flag.CommandLine.StringVar(&flagRoot, "root", "",
+		"render only the entry (and its descendants) with this id or path (i.e. 'topic 1/sub topic')")
+	flag.CommandLine.IntVar(&flagMaxDepth, "max-depth", 0, "render only the entries up to this depth (0 means no limit)")
+	flag.CommandLine.BoolVar(&flagMore, "more", false, "with -max-depth, show a '+N more' placeholder for the entries left out")
 
 	flag.CommandLine.StringVar(&flagImagesPath, "images-path", "", "folder in which to look for image files")
 	flag.CommandLine.StringVar(&flagImagesType, "images-type", "", "images file extension [png,jpg,svg]")
+	flag.CommandLine.BoolVar(&flagCheckIcons, "check-icons", true, "report the icon files that don't exist or are not images")
+	flag.CommandLine.BoolVar(&flagEmbedIcons, "embed-icons", false, "inline the icon files in the svg images drawn by Graphviz (the built-in svg renderer always does)")
+	flag.CommandLine.Var(&flagIDs, "ids", "node identifiers strategy [path,hash,random]")
+	flag.CommandLine.BoolVar(&flagStrict, "strict", false, "fail on malformed lines instead of just warning")
+	flag.CommandLine.Int64Var(&flagMaxSize, "max-size", 0, "maximum input size in bytes (0 means no limit)")
 
 	flag.CommandLine.Parse(os.Args[1:])
 }
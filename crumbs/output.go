@@ -0,0 +1,202 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+)
+
+// errDotNotFound is returned when the Graphviz dot binary can't be found.
+var errDotNotFound = errors.New("graphviz 'dot' not found (install Graphviz, or set -dot-path or GRAPHVIZ_DOT)")
+
+// writeOutput renders the entry tree in the named file, inferring
+// the format by the file extension: 'png', 'pdf' and 'svg' are drawn
+// by Graphviz, 'dot' (or 'gv') is the Graphviz script (a conflicting
+// -format is an error); for any other extension the -format output
+// is written.
+//
+// Without Graphviz the 'svg' files are drawn by the built-in renderer.
+func writeOutput(path string, entry *crumbs.Entry) error {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	format := strings.ToLower(flagFormat)
+
+	var buf bytes.Buffer
+	switch ext {
+	case "dot", "gv":
+		if format != "dot" {
+			return fmt.Errorf("cannot write the %s output format as a %s file", format, ext)
+		}
+		if err := checkEmbedIcons("dot"); err != nil {
+			return err
+		}
+		if err := render(&buf, entry, "dot"); err != nil {
+			return err
+		}
+	case "png", "pdf", "svg":
+		out, err := drawImage(entry, ext, format)
+		if err != nil {
+			return err
+		}
+		buf.Write(out)
+	default:
+		if err := checkEmbedIcons(format); err != nil {
+			return err
+		}
+		if err := render(&buf, entry, format); err != nil {
+			return err
+		}
+	}
+
+	return ioutil.WriteFile(path, buf.Bytes(), 0644)
+}
+
+// drawImage renders the entry tree as a Graphviz image.
+func drawImage(entry *crumbs.Entry, ext, format string) ([]byte, error) {
+	if format == "svg" && ext == "svg" {
+		var buf bytes.Buffer
+		err := render(&buf, entry, "svg")
+		return buf.Bytes(), err
+	}
+
+	if format != "dot" {
+		return nil, fmt.Errorf("cannot write the %s output format as a %s image", format, ext)
+	}
+	if err := checkEmbedIcons(ext); err != nil {
+		return nil, err
+	}
+
+	bin, err := dotPath()
+	if err != nil && ext == "svg" {
+		fmt.Fprintf(os.Stderr, "warning: %s, using the built-in svg renderer (-theme and -coloring are ignored)\n", err.Error())
+		var buf bytes.Buffer
+		err := render(&buf, entry, "svg")
+		return buf.Bytes(), err
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var script bytes.Buffer
+	if err := render(&script, entry, "dot"); err != nil {
+		return nil, err
+	}
+
+	out, err := runDot(bin, ext, script.Bytes())
+	if err != nil || !flagEmbedIcons {
+		return out, err
+	}
+
+	return embedImages(out, entry)
+}
+
+// checkEmbedIcons fails if -embed-icons is used with a non svg output.
+func checkEmbedIcons(format string) error {
+	if flagEmbedIcons && !strings.EqualFold(format, "svg") {
+		return fmt.Errorf("-embed-icons is supported only by the svg output format")
+	}
+	return nil
+}
+
+// dotPath returns the path of the Graphviz dot binary, looking
+// at the -dot-path flag, the GRAPHVIZ_DOT variable and the PATH.
+func dotPath() (string, error) {
+	name := "dot"
+	if flagDotPath != "" {
+		name = flagDotPath
+	} else if env := os.Getenv("GRAPHVIZ_DOT"); env != "" {
+		name = env
+	}
+
+	res, err := exec.LookPath(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errDotNotFound, err.Error())
+	}
+	return res, nil
+}
+
+// reDotLine matches the script line number in the Graphviz messages.
+var reDotLine = regexp.MustCompile(`line (\d+)`)
+
+// runDot pipes the script to the Graphviz dot binary and returns
+// the image; the Graphviz messages are reported together with
+// the script line they refer to.
+func runDot(bin, format string, script []byte) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+
+	cmd := exec.Command(bin, "-T"+format)
+	cmd.Stdin = bytes.NewReader(script)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+	err := cmd.Run()
+	msg := dotMessages(stderr.String(), script)
+	if err != nil {
+		return nil, fmt.Errorf("%s -T%s: %s\n%s", filepath.Base(bin), format, err.Error(), strings.TrimRight(msg, "\n"))
+	}
+	if msg != "" {
+		fmt.Fprint(os.Stderr, msg)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// dotMessages indents the Graphviz messages, each followed
+// by the script line it refers to (if any).
+func dotMessages(stderr string, script []byte) string {
+	lines := strings.Split(string(script), "\n")
+
+	var sb strings.Builder
+	for _, el := range strings.Split(strings.TrimSpace(stderr), "\n") {
+		if strings.TrimSpace(el) == "" {
+			continue
+		}
+		fmt.Fprintf(&sb, "  dot: %s\n", el)
+
+		res := reDotLine.FindStringSubmatch(el)
+		if len(res) == 0 {
+			continue
+		}
+		if n, err := strconv.Atoi(res[1]); err == nil && n > 0 && n <= len(lines) {
+			fmt.Fprintf(&sb, "  %5d | %s\n", n, strings.TrimSpace(lines[n-1]))
+		}
+	}
+
+	return sb.String()
+}
+
+// embedImages replaces the icon files referenced by
+// the Graphviz svg image with their data URIs.
+func embedImages(src []byte, note *crumbs.Entry) ([]byte, error) {
+	var err error
+	note.Walk(func(el *crumbs.Entry) {
+		icon := el.Icon()
+		if err != nil || icon == "" || crumbs.IsEmoji(icon) {
+			return
+		}
+
+		// the bundled icons are referenced as the files written for Graphviz
+		var path, uri string
+		if path, err = crumbs.IconFile(icon, ""); err != nil {
+			return
+		}
+		if uri, err = crumbs.IconDataURI(icon); err != nil {
+			err = fmt.Errorf("cannot embed icon: %w", err)
+			return
+		}
+
+		src = bytes.ReplaceAll(src,
+			[]byte(`xlink:href="`+html.EscapeString(path)+`"`),
+			[]byte(`xlink:href="`+uri+`"`))
+	})
+
+	return src, err
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDotMessages(t *testing.T) {
+	script := []byte("graph {\n\tn1[label=\"main\"];\n\tn1--n2[color=\"#\"];\n}\n")
+
+	tests := []struct {
+		stderr string
+		want   string
+	}{
+		{"", ""},
+		{"Warning: some font not found\n", "  dot: Warning: some font not found\n"},
+		{
+			"Error: <stdin>: syntax error in line 3 near '#'\n",
+			"  dot: Error: <stdin>: syntax error in line 3 near '#'\n" +
+				"      3 | n1--n2[color=\"#\"];\n",
+		},
+		{"Error: syntax error in line 42\n", "  dot: Error: syntax error in line 42\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.stderr, func(t *testing.T) {
+			assert.Equal(t, tt.want, dotMessages(tt.stderr, script))
+		})
+	}
+}
+
+func TestEmbedImages(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* [[../testdata/png/bulb.png]] main idea",
+		"** [[:rocket:]] topic",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src := []byte(`<svg><image xlink:href="../testdata/png/bulb.png" width="48px"/></svg>`)
+	got, err := embedImages(src, note)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(got), `<image xlink:href="data:image/png;base64,iVBORw0KGgo`)
+		assert.NotContains(t, string(got), "bulb.png")
+	}
+
+	note, err = crumbs.ParseLines([]string{"* [[nope.png]] main idea"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = embedImages(src, note)
+	assert.EqualError(t, err, "cannot embed icon: nope.png: no such file")
+}
+
+func TestEmbedBundledImages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crumbs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer os.Setenv("XDG_CACHE_HOME", os.Getenv("XDG_CACHE_HOME"))
+	os.Setenv("XDG_CACHE_HOME", dir)
+
+	note, err := crumbs.ParseLines([]string{"* [[:bulb:]] main idea"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "crumbs", "icons", "bulb.svg")
+	src := []byte(`<svg><image xlink:href="` + path + `" width="48px"/></svg>`)
+	got, err := embedImages(src, note)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(got), `<image xlink:href="data:image/svg+xml;base64,`)
+		assert.NotContains(t, string(got), "bulb.svg")
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crumbs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(format, theme, dotPath string) {
+		flagFormat, flagTheme, flagDotPath = format, theme, dotPath
+	}(flagFormat, flagTheme, flagDotPath)
+	flagTheme = "light"
+	flagDotPath = filepath.Join(dir, "no-dot")
+
+	note, err := crumbs.ParseLines([]string{"* main idea", "** topic"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		prefix string
+		err    error
+	}{
+		{"out.dot", "dot", "graph  {", nil},
+		{"out.gv", "dot", "graph  {", nil},
+		{"out.gv", "json", "", nil},
+		{"out.json", "json", "{", nil},
+		{"out.svg", "dot", `<?xml version="1.0"`, nil}, // the built-in renderer
+		{"out.svg", "svg", `<?xml version="1.0"`, nil},
+		{"out.png", "dot", "", errDotNotFound},
+		{"out.pdf", "mermaid", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.format, func(t *testing.T) {
+			flagFormat = tt.format
+			name := filepath.Join(dir, tt.name)
+			os.Remove(name)
+
+			err := writeOutput(name, note)
+			if tt.prefix == "" {
+				assert.Error(t, err)
+				if tt.err != nil {
+					assert.True(t, errors.Is(err, tt.err), err)
+				}
+				_, err := os.Stat(name)
+				assert.True(t, os.IsNotExist(err), "no file is written on errors")
+				return
+			}
+
+			if assert.NoError(t, err) {
+				got, err := ioutil.ReadFile(name)
+				if assert.NoError(t, err) {
+					assert.True(t, strings.HasPrefix(string(got), tt.prefix), string(got))
+				}
+			}
+		})
+	}
+}
+
+func TestWriteOutputGraphviz(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake dot binary is a shell script")
+	}
+
+	dir, err := ioutil.TempDir("", "crumbs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	defer func(format, theme, dotPath string) {
+		flagFormat, flagTheme, flagDotPath = format, theme, dotPath
+	}(flagFormat, flagTheme, flagDotPath)
+	flagFormat, flagTheme = "dot", "light"
+
+	// a fake dot that writes the requested format and the script
+	flagDotPath = filepath.Join(dir, "dot")
+	fake := "#!/bin/sh\necho \"$1\"\ncat\n"
+	if err := ioutil.WriteFile(flagDotPath, []byte(fake), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	note, err := crumbs.ParseLines([]string{"* main idea"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	name := filepath.Join(dir, "out.png")
+	if err := writeOutput(name, note); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ioutil.ReadFile(name)
+	if assert.NoError(t, err) {
+		assert.True(t, strings.HasPrefix(string(got), "-Tpng\ngraph  {"), string(got))
+	}
+
+	// a failing dot
+	fake = "#!/bin/sh\ncat > /dev/null\necho \"Error: <stdin>: syntax error in line 1 near 'graph'\" >&2\nexit 1\n"
+	if err := ioutil.WriteFile(flagDotPath, []byte(fake), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err = writeOutput(filepath.Join(dir, "out.pdf"), note)
+	assert.EqualError(t, err, "dot -Tpdf: exit status 1\n"+
+		"  dot: Error: <stdin>: syntax error in line 1 near 'graph'\n"+
+		"      1 | graph  {")
+}
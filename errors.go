@@ -0,0 +1,80 @@
+package crumbs
+
+import "fmt"
+
+// ErrorKind classifies a parse diagnostic.
+type ErrorKind int
+
+const (
+	// OrphanText is a non empty line without leading stars.
+	OrphanText ErrorKind = iota + 1
+	// LevelJump is a line more than one level deeper than the previous one.
+	LevelJump
+	// EmptyHeading is a line made only of stars.
+	EmptyHeading
+	// MalformedIcon is an icon marker without name or closing brackets.
+	MalformedIcon
+	// UnknownIcon is an icon marker that cannot be resolved.
+	UnknownIcon
+	// BadIndent is a leading whitespace that does not
+	// match the indentation unit (tabs or N spaces).
+	BadIndent
+	// DuplicateAnchor is an anchor already given to another entry.
+	DuplicateAnchor
+	// DanglingLink is a link to an anchor not given to any entry.
+	DanglingLink
+	// MissingIcon is an icon marker that refers to a missing file.
+	MissingIcon
+	// BadIconFormat is an icon marker that refers to a file
+	// that is not an image (png, jpeg, gif or svg).
+	BadIconFormat
+)
+
+var errorKindNames = map[ErrorKind]string{
+	OrphanText:      "orphan text",
+	LevelJump:       "level jump",
+	EmptyHeading:    "empty heading",
+	MalformedIcon:   "malformed icon marker",
+	UnknownIcon:     "unknown icon",
+	BadIndent:       "inconsistent indentation",
+	DuplicateAnchor: "duplicate anchor",
+	DanglingLink:    "dangling link",
+	MissingIcon:     "missing icon",
+	BadIconFormat:   "bad icon format",
+}
+
+// String returns the diagnostic kind description.
+func (k ErrorKind) String() string {
+	if name, ok := errorKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("ErrorKind(%d)", int(k))
+}
+
+// ParseError is a diagnostic about a source line.
+// Line and Column are 1-based.
+type ParseError struct {
+	Line   int
+	Column int
+	Kind   ErrorKind
+	Msg    string
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Msg)
+}
+
+// ParseErrors is a list of *ParseError.
+type ParseErrors []*ParseError
+
+// Error implements the error interface.
+func (p ParseErrors) Error() string {
+	switch len(p) {
+	case 0:
+		return "no errors"
+	case 1:
+		return p[0].Error()
+	}
+	return fmt.Sprintf("%s (and %d more errors)", p[0], len(p)-1)
+}
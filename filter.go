@@ -0,0 +1,36 @@
+package crumbs
+
+// Filter returns a copy of the tree made only of the entries
+// that match and of their ancestors (so that the tree stays connected).
+// The links to the entries left out are dropped.
+func Filter(note *Entry, match func(*Entry) bool) *Entry {
+	copies := map[*Entry]*Entry{}
+
+	var visit func(el *Entry) *Entry
+	visit = func(el *Entry) *Entry {
+		var childrens []*Entry
+		for _, c := range el.childrens {
+			if res := visit(c); res != nil {
+				childrens = append(childrens, res)
+			}
+		}
+
+		if len(childrens) == 0 && el.parent != nil && !match(el) {
+			return nil
+		}
+
+		res := *el
+		res.childrens = childrens
+		for _, c := range childrens {
+			c.parent = &res
+		}
+		copies[el] = &res
+
+		return &res
+	}
+
+	res := visit(note.Root())
+	relink(res, copies)
+
+	return res
+}
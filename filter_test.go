@@ -0,0 +1,48 @@
+package crumbs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	note, err := ParseLines([]string{
+		"* main idea",
+		"** topic 1",
+		"*** login -> #auth #backend",
+		"*** signup -> #auth",
+		"** topic 2",
+		"*** authentication {#auth} #backend",
+		"**** token",
+		"* other idea",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := Filter(note, func(e *Entry) bool { return e.HasTag("backend") })
+
+	var sb strings.Builder
+	if err := Format(&sb, got); err != nil {
+		t.Fatal(err)
+	}
+	want := "* main idea\n" +
+		"** topic 1\n" +
+		"*** login -> #auth #backend\n" +
+		"** topic 2\n" +
+		"*** authentication {#auth} #backend\n"
+	assert.Equal(t, want, sb.String())
+
+	// the links point to the copies
+	login := got.childrens[0].childrens[0].childrens[0]
+	auth := got.childrens[0].childrens[1].childrens[0]
+	assert.Equal(t, []*Entry{auth}, login.Links())
+	assert.Equal(t, got.childrens[0].childrens[1], auth.Parent())
+	assert.Equal(t, "n1.2.1", auth.ID())
+
+	// the source tree is unchanged
+	assert.Equal(t, 2, len(note.childrens))
+	assert.Equal(t, 1, len(note.childrens[0].childrens[1].childrens[0].childrens))
+}
@@ -0,0 +1,81 @@
+package crumbs
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Format writes the entry tree in the canonical crumbs syntax:
+// one line for each entry made of as many stars as the entry
+// depth (so the level jumps are fixed), a single space, the icon
+// marker (if any) and the text without surrounding whitespace.
+// The anchor, the attributes, the links and the tags follow the text,
+// the note lines follow the entry, indented as the entry text.
+//
+// The icon is written as it is, so parse the source with an
+// IconResolverFunc that returns the name to keep the icon names.
+func Format(wr io.Writer, note *Entry) error {
+	bw := bufio.NewWriter(wr)
+
+	for _, el := range note.Root().Childrens() {
+		formatEntry(bw, el, 1)
+	}
+
+	return bw.Flush()
+}
+
+// hasHashtag reports whether the text contains the hashtag.
+func hasHashtag(text, tag string) bool {
+	for _, res := range reTag.FindAllStringSubmatch(text, -1) {
+		if res[1] == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// formatEntry writes the entry (at the given depth) and its children.
+func formatEntry(wr *bufio.Writer, el *Entry, depth int) {
+	wr.WriteString(strings.Repeat("*", depth))
+
+	var parts []string
+	if icon := strings.TrimSpace(el.icon); icon != "" {
+		parts = append(parts, "[["+icon+"]]")
+	}
+	if text := strings.TrimSpace(el.text); text != "" {
+		parts = append(parts, text)
+	}
+	if el.anchor != "" {
+		parts = append(parts, "{#"+el.anchor+"}")
+	}
+	if attrs := formatAttrs(el.attrs); attrs != "" {
+		parts = append(parts, attrs)
+	}
+	for _, ref := range el.refs {
+		parts = append(parts, "-> #"+ref)
+	}
+	for _, tag := range el.tags {
+		if !hasHashtag(el.text, tag) {
+			parts = append(parts, "#"+tag)
+		}
+	}
+	if len(parts) > 0 {
+		wr.WriteString(" ")
+		wr.WriteString(strings.Join(parts, " "))
+	}
+	wr.WriteString("\n")
+
+	indent := strings.Repeat(" ", depth+1)
+	for _, line := range strings.Split(el.note, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			wr.WriteString(indent)
+			wr.WriteString(line)
+			wr.WriteString("\n")
+		}
+	}
+
+	for _, child := range el.childrens {
+		formatEntry(wr, child, depth+1)
+	}
+}
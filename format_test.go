@@ -0,0 +1,71 @@
+package crumbs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{
+			"*main idea   \n**   topic 1\n\n***\tsub topic\n",
+			"* main idea\n** topic 1\n*** sub topic\n",
+		},
+		{
+			"* [[ bulb ]]   main idea\n** [[ comments-alt.png]]topic\n",
+			"* [[bulb]] main idea\n** [[comments-alt.png]] topic\n",
+		},
+		{
+			"* main idea\n**** too deep\n** topic\n",
+			"* main idea\n** too deep\n** topic\n",
+		},
+		{
+			"* main idea\n\tnote\n** topic\\\n  first line   \n\n    second line\n",
+			"* main idea\n  note\n** topic\n   first line\n   second line\n",
+		},
+		{
+			"* main idea   {#main}\n** topic ->#main   -> #other\n** other{#other}\n",
+			"* main idea {#main}\n** topic -> #main -> #other\n** other {#other}\n",
+		},
+		{
+			"* main idea {shape=box   color=#e76f51 bold}\n** topic {label=\"a b\"} {#t} -> #t\n",
+			"* main idea {bold color=#e76f51 shape=box}\n** topic {#t} {label=\"a b\"} -> #t\n",
+		},
+		{
+			"* main idea\n**\n",
+			"* main idea\n**\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			note, err := Parse(strings.NewReader(tt.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var sb strings.Builder
+			if err := Format(&sb, note); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.want, sb.String())
+
+			// formatting is idempotent
+			note, err = Parse(strings.NewReader(sb.String()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var again strings.Builder
+			if err := Format(&again, note); err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, sb.String(), again.String())
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package freemind
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+const sample = `<map version="1.0.1">
+  <node ID="ID_n1" TEXT="main idea" COLOR="#990000">
+    <icon BUILTIN="idea"></icon>
+    <node ID="ID_n1_1" TEXT="topic 1" FOLDED="true" POSITION="right">
+      <node ID="ID_n1_1_1" TEXT="sub topic" LINK="https://github.com/lucasepe/crumbs"></node>
+      <node ID="ID_n1_1_2" TEXT="sub topic" BACKGROUND_COLOR="#ffcdb2"></node>
+    </node>
+    <node ID="ID_n1_2" TEXT="topic 2" POSITION="left">
+      <icon BUILTIN="button_ok"></icon>
+      <richcontent TYPE="NOTE">
+        <html>
+          <body>
+            <p>first line</p>
+            <p>second line</p>
+          </body>
+        </html>
+      </richcontent>
+    </node>
+  </node>
+</map>
+`
+
+func TestRead(t *testing.T) {
+	got, err := Read(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := got.Childrens()[0]
+	assert.Equal(t, "main idea", main.Text())
+	assert.Equal(t, "idea", main.Icon())
+	assert.Equal(t, "#990000", main.Attr("fontcolor"))
+	// the text color is not the (inherited) branch color
+	assert.Equal(t, "", main.Attr("color"))
+
+	topic := main.Childrens()[0]
+	assert.Equal(t, "true", topic.Attr("folded"))
+	assert.Equal(t, "right", topic.Attr("position"))
+	assert.Equal(t, 2, len(topic.Childrens()))
+	assert.Equal(t, "https://github.com/lucasepe/crumbs", topic.Childrens()[0].Attr("link"))
+	assert.Equal(t, "#ffcdb2", topic.Childrens()[1].Attr("fillcolor"))
+
+	assert.Equal(t, "button_ok", main.Childrens()[1].Icon())
+	assert.Equal(t, "first line\nsecond line", main.Childrens()[1].Note())
+	assert.Equal(t, "left", main.Childrens()[1].Attr("position"))
+}
+
+func TestReadRichContent(t *testing.T) {
+	src := `<map version="1.0.1">
+<node ID="ID_1">
+<richcontent TYPE="NODE"><html><body><p>main <b>idea</b></p></body></html></richcontent>
+<node TEXT="topic 1"/>
+<richcontent TYPE="NOTE"><html><body><p>a <i>long</i>
+  note</p><p>on two lines</p></body></html></richcontent>
+</node>
+</map>`
+
+	got, err := Read(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := got.Childrens()[0]
+	assert.Equal(t, "main idea", main.Text())
+	assert.Equal(t, "topic 1", main.Childrens()[0].Text())
+	assert.Equal(t, "a long note\non two lines", main.Note())
+}
+
+func TestReadKeepsText(t *testing.T) {
+	src := `<map version="1.0.1">
+  <node ID="ID_n1" TEXT="Meeting notes {draft}">
+    <node ID="ID_n1_1" TEXT="Budget for #q3 review #todo"></node>
+    <node ID="ID_n1_2" TEXT="Call -&gt; #alice"></node>
+  </node>
+</map>
+`
+
+	var warns []*crumbs.ParseError
+	got, err := Read(strings.NewReader(src), crumbs.Warn(func(e *crumbs.ParseError) {
+		warns = append(warns, e)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, warns)
+
+	main := got.Childrens()[0]
+	assert.Equal(t, "Meeting notes {draft}", main.Text())
+	assert.Empty(t, main.Attrs())
+	assert.Equal(t, "Budget for #q3 review #todo", main.Childrens()[0].Text())
+	assert.Empty(t, main.Childrens()[0].Tags())
+	assert.Equal(t, "Call -> #alice", main.Childrens()[1].Text())
+	assert.Empty(t, main.Childrens()[1].Links())
+
+	var buf bytes.Buffer
+	if err := Write(&buf, got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, src, buf.String())
+}
+
+func TestReadMaxSize(t *testing.T) {
+	_, err := Read(strings.NewReader(sample), crumbs.MaxSize(int64(len(sample))-1))
+	assert.True(t, errors.Is(err, crumbs.ErrTooLarge), err)
+
+	_, err = Read(strings.NewReader(sample), crumbs.MaxSize(int64(len(sample))))
+	assert.NoError(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	note, err := Read(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, note); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, sample, buf.String())
+}
+
+func TestWriteManyRoots(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{"* one", "* two"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Error(t, Write(&bytes.Buffer{}, note))
+}
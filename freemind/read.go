@@ -0,0 +1,177 @@
+package freemind
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/text"
+)
+
+// attributes maps the FreeMind node attributes to the entry attributes.
+var attributes = map[string]string{
+	"COLOR":            "fontcolor",
+	"BACKGROUND_COLOR": "fillcolor",
+	"FOLDED":           "folded",
+	"LINK":             "link",
+	"POSITION":         "position",
+}
+
+// pending is a node whose contents (text, icons) are not
+// complete until the first child node (or the node end).
+type pending struct {
+	line  int
+	depth int
+	text  string
+	note  string
+	icon  string
+	attrs map[string]string
+}
+
+// Read builds the entry tree from a FreeMind (or Freeplane) mind map.
+// The builtin icons names are resolved as the crumbs icon markers.
+func Read(r io.Reader, opts ...crumbs.ParseOption) (*crumbs.Entry, error) {
+	b, err := crumbs.NewBuilder(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := ioutil.ReadAll(b.Limit(r))
+	if err != nil {
+		return nil, err
+	}
+
+	// the last added entry for each depth
+	var added []*crumbs.Entry
+	var cur *pending
+
+	flush := func() error {
+		if cur == nil {
+			return nil
+		}
+
+		e, err := b.Add(cur.line, cur.depth, cur.text)
+		if err != nil {
+			return err
+		}
+		b.SetIcon(e, cur.icon)
+		b.SetNote(e, cur.note)
+		for k, v := range cur.attrs {
+			b.SetAttr(e, k, v)
+		}
+
+		added = append(added[:cur.depth-1], e)
+		cur = nil
+
+		return nil
+	}
+
+	lines := text.LineCounter(src)
+
+	dec := xml.NewDecoder(bytes.NewReader(src))
+	// rich is the type of the current richcontent element (NODE or NOTE)
+	depth, rich := 0, ""
+	var richText strings.Builder
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("freemind: line %d: %w", lines(dec.InputOffset()), err)
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch el.Name.Local {
+			case "node":
+				if err := flush(); err != nil {
+					return nil, err
+				}
+
+				depth++
+				cur = &pending{
+					line:  lines(dec.InputOffset()),
+					depth: depth,
+					text:  attr(el, "TEXT"),
+					attrs: map[string]string{},
+				}
+				for name, key := range attributes {
+					cur.attrs[key] = attr(el, name)
+				}
+
+			case "icon":
+				name := attr(el, "BUILTIN")
+				if cur != nil && cur.icon == "" {
+					cur.icon = name
+				} else if cur == nil && depth > 0 && depth <= len(added) && added[depth-1].Icon() == "" {
+					b.SetIcon(added[depth-1], name)
+				}
+
+			case "richcontent":
+				rich = attr(el, "TYPE")
+				richText.Reset()
+
+			case "p", "br":
+				// a new line of the note
+				if rich == "NOTE" {
+					richText.WriteString("\n")
+				}
+			}
+
+		case xml.CharData:
+			if rich != "" {
+				// the HTML line breaks are just whitespace
+				richText.Write(bytes.Replace(el, []byte("\n"), []byte(" "), -1))
+			}
+
+		case xml.EndElement:
+			switch el.Name.Local {
+			case "node":
+				if err := flush(); err != nil {
+					return nil, err
+				}
+				depth--
+
+			case "richcontent":
+				switch {
+				case rich == "NODE" && cur != nil && cur.text == "":
+					cur.text = strings.Join(strings.Fields(richText.String()), " ")
+				case rich == "NOTE" && cur != nil:
+					cur.note = noteText(richText.String())
+				case rich == "NOTE" && depth > 0 && depth <= len(added):
+					b.SetNote(added[depth-1], noteText(richText.String()))
+				}
+				rich = ""
+			}
+		}
+	}
+
+	return b.Tree()
+}
+
+// noteText collapses the whitespace of the note
+// lines and removes the empty ones.
+func noteText(str string) string {
+	var lines []string
+	for _, el := range strings.Split(str, "\n") {
+		if line := strings.Join(strings.Fields(el), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// attr returns the value of the named attribute.
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,92 @@
+package freemind
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+)
+
+type mindmap struct {
+	XMLName xml.Name `xml:"map"`
+	Version string   `xml:"version,attr"`
+	Node    node     `xml:"node"`
+}
+
+type node struct {
+	ID         string       `xml:"ID,attr,omitempty"`
+	Text       string       `xml:"TEXT,attr"`
+	Color      string       `xml:"COLOR,attr,omitempty"`
+	Background string       `xml:"BACKGROUND_COLOR,attr,omitempty"`
+	Folded     string       `xml:"FOLDED,attr,omitempty"`
+	Link       string       `xml:"LINK,attr,omitempty"`
+	Position   string       `xml:"POSITION,attr,omitempty"`
+	Icons      []icon       `xml:"icon"`
+	Note       *richContent `xml:"richcontent,omitempty"`
+	Nodes      []node       `xml:"node"`
+}
+
+// richContent is an HTML note, one paragraph for each line.
+type richContent struct {
+	Type  string   `xml:"TYPE,attr"`
+	Lines []string `xml:"html>body>p"`
+}
+
+type icon struct {
+	Builtin string `xml:"BUILTIN,attr"`
+}
+
+// Write serializes the entry tree as a FreeMind mind map.
+// The icons are written as builtin icons named as the
+// icon file (without folder and extension).
+func Write(wr io.Writer, note *crumbs.Entry) error {
+	roots := note.Root().Childrens()
+	if len(roots) != 1 {
+		return fmt.Errorf("freemind mind map must have one main idea, found %d", len(roots))
+	}
+
+	doc := mindmap{
+		Version: "1.0.1",
+		Node:    newNode(roots[0]),
+	}
+
+	enc := xml.NewEncoder(wr)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(wr, "\n")
+	return err
+}
+
+// newNode creates the node element for the entry (and its children).
+func newNode(el *crumbs.Entry) node {
+	res := node{
+		ID:         "ID_" + strings.NewReplacer(".", "_", "-", "_").Replace(el.ID()),
+		Text:       strings.TrimSpace(el.Text()),
+		Color:      el.Attr("fontcolor"),
+		Background: el.Attr("fillcolor"),
+		Folded:     el.Attr("folded"),
+		Link:       el.Attr("link"),
+		Position:   el.Attr("position"),
+	}
+
+	if src := el.Icon(); len(src) > 0 && !crumbs.IsEmoji(src) && !crumbs.IsBundledIcon(src) {
+		name := strings.TrimSuffix(filepath.Base(el.Icon()), filepath.Ext(el.Icon()))
+		res.Icons = append(res.Icons, icon{Builtin: name})
+	}
+
+	if note := strings.TrimSpace(el.Note()); note != "" {
+		res.Note = &richContent{Type: "NOTE", Lines: strings.Split(note, "\n")}
+	}
+
+	for _, child := range el.Childrens() {
+		res.Nodes = append(res.Nodes, newNode(child))
+	}
+
+	return res
+}
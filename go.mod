@@ -6,4 +6,5 @@ require (
 	github.com/emicklei/dot v0.14.0
 	github.com/stretchr/testify v1.6.1
 	github.com/teris-io/shortid v0.0.0-20171029131806-771a37caa5cf
+	gopkg.in/yaml.v3 v3.0.1
 )
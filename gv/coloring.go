@@ -0,0 +1,123 @@
+package gv
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/palette"
+)
+
+// Coloring defines how the tree edges are colored.
+type Coloring int
+
+const (
+	// ColorByLevel colors the edges with the theme
+	// color of the depth level of the target node.
+	ColorByLevel Coloring = iota
+	// ColorByBranch gives each branch (a child of a main idea)
+	// its own hue, with lighter shades for the deeper nodes.
+	ColorByBranch
+	// ColorByTag colors the edges as the first tag
+	// of the target node (or of its nearest tagged ancestor).
+	ColorByTag
+	// NoColoring draws all the edges with the theme edge color.
+	NoColoring
+)
+
+var coloringNames = map[Coloring]string{
+	ColorByLevel:  "by-level",
+	ColorByBranch: "by-branch",
+	ColorByTag:    "by-tag",
+	NoColoring:    "none",
+}
+
+// String returns the strategy name.
+func (c Coloring) String() string {
+	if name, ok := coloringNames[c]; ok {
+		return name
+	}
+	return fmt.Sprintf("Coloring(%d)", int(c))
+}
+
+// Set sets the strategy by name, so that a Coloring
+// can be used as a command line flag value.
+func (c *Coloring) Set(name string) error {
+	for k, v := range coloringNames {
+		if strings.EqualFold(v, strings.TrimSpace(name)) {
+			*c = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown coloring '%s' (valid values are: by-level, by-branch, by-tag, none)", name)
+}
+
+const (
+	// branchHue is the hue (in degrees) of the first branch
+	branchHue = 173.0
+	// branchSaturation is the saturation of the branches colors
+	branchSaturation = 0.6
+	// branchLightness is the lightness of the branches colors
+	branchLightness = 0.4
+	// branchTint is the lightness added at each depth level
+	branchTint = 0.1
+	// maxLightness keeps the deepest edges visible
+	maxLightness = 0.8
+)
+
+// edgeColorMaker returns a function that supplies
+// the color of the edge to the entry.
+func edgeColorMaker(c Coloring, note *crumbs.Entry, th *Theme, tagColor func(string) string) func(*crumbs.Entry) string {
+	switch c {
+	case ColorByBranch:
+		return branchColors(note, th)
+	case ColorByTag:
+		return func(el *crumbs.Entry) string {
+			for ; el != nil; el = el.Parent() {
+				if tags := el.Tags(); len(tags) > 0 {
+					return tagColor(tags[0])
+				}
+			}
+			return th.Edge.Color
+		}
+	case NoColoring:
+		return func(*crumbs.Entry) string {
+			return th.Edge.Color
+		}
+	default:
+		return func(el *crumbs.Entry) string {
+			return th.edgeColor(el.Level())
+		}
+	}
+}
+
+// branchColors spreads the hues evenly among the branches
+// (the level 2 entries), so that they are distinguishable
+// whatever their number; the main ideas get the theme color.
+func branchColors(note *crumbs.Entry, th *Theme) func(*crumbs.Entry) string {
+	var branches []*crumbs.Entry
+	for _, el := range note.Root().Childrens() {
+		branches = append(branches, el.Childrens()...)
+	}
+
+	hues := make(map[*crumbs.Entry]float64, len(branches))
+	for i, el := range branches {
+		hues[el] = branchHue + float64(i)*360/float64(len(branches))
+	}
+
+	return func(el *crumbs.Entry) string {
+		lvl := el.Level()
+		for el != nil && el.Level() > 2 {
+			el = el.Parent()
+		}
+
+		hue, ok := hues[el]
+		if !ok {
+			return th.edgeColor(lvl)
+		}
+
+		lum := math.Min(branchLightness+float64(lvl-2)*branchTint, maxLightness)
+		return palette.HSL(hue, branchSaturation, lum)
+	}
+}
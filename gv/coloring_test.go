@@ -0,0 +1,78 @@
+package gv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColoringSet(t *testing.T) {
+	tests := []struct {
+		name string
+		want Coloring
+	}{
+		{"by-level", ColorByLevel},
+		{"By-Branch", ColorByBranch},
+		{" by-tag ", ColorByTag},
+		{"none", NoColoring},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Coloring
+			if assert.NoError(t, got.Set(tt.name)) {
+				assert.Equal(t, tt.want, got)
+				assert.Equal(t, strings.ToLower(strings.TrimSpace(tt.name)), got.String())
+			}
+		})
+	}
+
+	var c Coloring
+	assert.EqualError(t, c.Set("rainbow"),
+		"unknown coloring 'rainbow' (valid values are: by-level, by-branch, by-tag, none)")
+}
+
+func TestEdgeColorMaker(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* main idea",
+		"** first #red",
+		"*** sub topic",
+		"**** deep topic",
+		"***** deeper topic",
+		"****** deepest topic",
+		"******* abyss",
+		"** second",
+		"** third",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	th := LightTheme()
+	tagColor := func(tag string) string { return "#" + tag }
+
+	main := note.Childrens()[0]
+	first, second := main.Childrens()[0], main.Childrens()[1]
+	sub := first.Childrens()[0]
+	abyss := note.FindByText("abyss")[0]
+
+	byBranch := edgeColorMaker(ColorByBranch, note, th, tagColor)
+	assert.Equal(t, th.edgeColor(1), byBranch(main))
+	assert.Equal(t, "#29A395", byBranch(first))
+	assert.Equal(t, "#33CCBA", byBranch(sub))
+	assert.NotEqual(t, byBranch(first), byBranch(second))
+	// the shades stop getting lighter
+	assert.Equal(t, "#ADEBE3", byBranch(abyss))
+
+	byTag := edgeColorMaker(ColorByTag, note, th, tagColor)
+	assert.Equal(t, "#red", byTag(sub))
+	assert.Equal(t, th.Edge.Color, byTag(second))
+
+	none := edgeColorMaker(NoColoring, note, th, tagColor)
+	assert.Equal(t, th.Edge.Color, none(sub))
+
+	byLevel := edgeColorMaker(ColorByLevel, note, th, tagColor)
+	assert.Equal(t, th.edgeColor(3), byLevel(sub))
+}
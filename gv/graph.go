@@ -35,6 +35,42 @@ func Vertical(set bool) GraphOption {
 	}
 }
 
+// Radial enables/disables the radial layout, meant for the
+// 'twopi' layout engine: the root node is in the center and
+// each level lies on a concentric circle around it.
+func Radial(set bool) GraphOption {
+	return func(gr *dot.Graph) {
+		if !set {
+			return
+		}
+
+		gr.Delete("rankdir")
+		gr.Attr("layout", "twopi")
+		// the radius of the first ring, then the distance between the others
+		gr.Attr("ranksep", "2.3:1.8:1.4:1.2")
+		gr.Attr("overlap", "false")
+		gr.Attr("splines", "true")
+	}
+}
+
+// Themed sets the graph attributes defined by the theme.
+func Themed(t *Theme) GraphOption {
+	return func(gr *dot.Graph) {
+		if t == nil {
+			return
+		}
+
+		if t.Graph.Background != "" {
+			gr.Attr("bgcolor", t.Graph.Background)
+		}
+		gr.Attr("fontname", t.Graph.FontName)
+		gr.Attr("fontsize", strconv.Itoa(t.Graph.FontSize))
+		gr.Attr("ranksep", ftoa(t.Graph.RankSep))
+		gr.Attr("nodesep", ftoa(t.Graph.NodeSep))
+		gr.Attr("pad", ftoa(t.Graph.Pad))
+	}
+}
+
 // newGraph returns a new GraphViz DOT language graph
 func newGraph(opts ...GraphOption) *dot.Graph {
 	res := dot.NewGraph(dot.Undirected)
@@ -56,8 +92,10 @@ func newGraph(opts ...GraphOption) *dot.Graph {
 	return res
 }
 
-// createEdge creates a new connection line between two nodes
-func createEdge(gr *dot.Graph, fid, tid string, color string) error {
+// createEdge creates a new connection line between two nodes.
+// You can customize some attributes using
+// the variadic edge attributes.
+func createEdge(gr *dot.Graph, fid, tid string, color string, opts ...edgeAttribute) error {
 	a, ok := gr.FindNodeById(fid)
 	if !ok {
 		return fmt.Errorf("node with id=%s not found", fid)
@@ -81,9 +119,72 @@ func createEdge(gr *dot.Graph, fid, tid string, color string) error {
 		res.Attr("color", "#ced4da")
 	}
 
+	for _, opt := range opts {
+		opt(&res)
+	}
+
+	return nil
+}
+
+// createLink creates a new dashed connection line between
+// two nodes that is not used in ranking the nodes (so that
+// it does not distort the tree layout)
+func createLink(gr *dot.Graph, fid, tid string, opts ...edgeAttribute) error {
+	a, ok := gr.FindNodeById(fid)
+	if !ok {
+		return fmt.Errorf("node with id=%s not found", fid)
+	}
+
+	b, ok := gr.FindNodeById(tid)
+	if !ok {
+		return fmt.Errorf("node with id=%s not found", tid)
+	}
+
+	res := gr.Edge(a, b)
+	res.Attr("style", "dashed")
+	res.Attr("constraint", "false")
+	res.Attr("dir", "forward")
+	res.Attr("penwidth", "1.5")
+	res.Attr("color", "#adb5bd")
+
+	for _, opt := range opts {
+		opt(&res)
+	}
+
 	return nil
 }
 
+// edgeAttribute defines a function that
+// apply a property to an edge
+type edgeAttribute func(*dot.Edge)
+
+// edgeColor sets the edge color
+func edgeColor(hex string) edgeAttribute {
+	return func(el *dot.Edge) {
+		if strings.TrimSpace(hex) != "" {
+			el.Attr("color", hex)
+		}
+	}
+}
+
+// edgePenWidth sets the edge line width, in points
+func edgePenWidth(w float64) edgeAttribute {
+	return func(el *dot.Edge) {
+		if w > 0 {
+			el.Attr("penwidth", ftoa(w))
+		}
+	}
+}
+
+// edgeFontName sets the font used for the edge labels
+func edgeFontName(name string) edgeAttribute {
+	return func(el *dot.Edge) {
+		if strings.TrimSpace(name) != "" {
+			el.Attr("fontname", name)
+		}
+	}
+}
+
 // createNode create and adds a new node to the graph.
 // You can customize some attributes using
 // the variadic node attributes.
@@ -107,6 +208,23 @@ func createNode(g *dot.Graph, id string, opts ...nodeAttribute) string {
 // apply a property to a node
 type nodeAttribute func(*dot.Node)
 
+// nodeTheme sets the node attributes defined by the theme
+func nodeTheme(t *Theme) nodeAttribute {
+	return func(el *dot.Node) {
+		if t == nil {
+			return
+		}
+
+		el.Attr("fontname", t.Node.FontName)
+		el.Attr("fontsize", strconv.Itoa(t.Node.FontSize))
+		el.Attr("width", ftoa(t.Node.Width))
+		el.Attr("margin", t.Node.Margin)
+		if t.Node.FontColor != "" {
+			el.Attr("fontcolor", t.Node.FontColor)
+		}
+	}
+}
+
 // nodeFillColor sets the node fill color
 func nodeFillColor(hex string) nodeAttribute {
 	return func(el *dot.Node) {
@@ -115,24 +233,40 @@ func nodeFillColor(hex string) nodeAttribute {
 		}
 
 		el.Attr("fillcolor", hex)
+		addStyle(el, "filled")
+	}
+}
 
-		const attr = "filled"
-
-		val := el.AttributesMap.Value("style")
-		if val == nil {
-			el.Attr("style", attr)
+// nodeBorder draws a rounded border around the node
+func nodeBorder(hex string) nodeAttribute {
+	return func(el *dot.Node) {
+		if strings.TrimSpace(hex) == "" {
 			return
 		}
 
-		style, ok := val.(string)
-		if !ok {
-			el.Attr("style", attr)
-			return
-		}
+		el.Attr("shape", "box")
+		el.Attr("color", hex)
+		el.Attr("penwidth", "1.5")
+		addStyle(el, "rounded")
+	}
+}
 
-		if _, found := text.Find(strings.Split(style, ","), attr); !found {
-			el.Attr("style", strings.Join([]string{style, attr}, ","))
-		}
+// addStyle adds the attr to the node style (if not already there)
+func addStyle(el *dot.Node, attr string) {
+	val := el.AttributesMap.Value("style")
+	if val == nil {
+		el.Attr("style", attr)
+		return
+	}
+
+	style, ok := val.(string)
+	if !ok {
+		el.Attr("style", attr)
+		return
+	}
+
+	if _, found := text.Find(strings.Split(style, ","), attr); !found {
+		el.Attr("style", strings.Join([]string{style, attr}, ","))
 	}
 }
 
@@ -2,10 +2,15 @@ package gv
 
 import (
 	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/emicklei/dot"
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestNewNodeOptions(t *testing.T) {
@@ -15,12 +20,12 @@ func TestNewNodeOptions(t *testing.T) {
 	}{
 		{
 			[]nodeAttribute{},
-			`digraph  {n1[fontname="Fira Code",fontsize="12",label="",margin="0.2,0.2",width="2"];}`,
+			`digraph  {n1[fontname="Fira Code",fontsize="12",label="",margin="0.2,0.2",shape="plain",width="2"];}`,
 		},
 
 		{
 			[]nodeAttribute{nodeFillColor("#ff0000")},
-			`digraph  {n1[fillcolor="#ff0000",fontname="Fira Code",fontsize="12",label="",margin="0.2,0.2",style="filled",width="2"];}`,
+			`digraph  {n1[fillcolor="#ff0000",fontname="Fira Code",fontsize="12",label="",margin="0.2,0.2",shape="plain",style="filled",width="2"];}`,
 		},
 
 		{
@@ -28,6 +33,11 @@ func TestNewNodeOptions(t *testing.T) {
 			`digraph  {n1[fontname="Fira Code",fontsize="12",label="",margin="0.2,0.2",shape="box",width="2"];}`,
 		},
 
+		{
+			[]nodeAttribute{nodeFillColor("#00ff00"), nodeBorder("#ff0000")},
+			`digraph  {n1[color="#ff0000",fillcolor="#00ff00",fontname="Fira Code",fontsize="12",label="",margin="0.2,0.2",penwidth="1.5",shape="box",style="filled,rounded",width="2"];}`,
+		},
+
 		{
 			[]nodeAttribute{nodeShape("hexagon"), nodeFillColor("#00ff00")},
 			`digraph  {n1[fillcolor="#00ff00",fontname="Fira Code",fontsize="12",label="",margin="0.2,0.2",shape="hexagon",style="filled",width="2"];}`,
@@ -52,11 +62,11 @@ func TestNodeHTMLabel(t *testing.T) {
 	}{
 		{
 			`<b>Bold</b>`,
-			`digraph  {n1[fontname="Fira Code",fontsize="12",label=<<b>Bold</b>>,margin="0.2,0.2",width="2"];}`,
+			`digraph  {n1[fontname="Fira Code",fontsize="12",label=<<b>Bold</b>>,margin="0.2,0.2",shape="plain",width="2"];}`,
 		},
 		{
 			`<table><tr><td>col 1</td></tr></table>`,
-			`digraph  {n1[fontname="Fira Code",fontsize="12",label=<<table><tr><td>col 1</td></tr></table>>,margin="0.2,0.2",width="2"];}`,
+			`digraph  {n1[fontname="Fira Code",fontsize="12",label=<<table><tr><td>col 1</td></tr></table>>,margin="0.2,0.2",shape="plain",width="2"];}`,
 		},
 	}
 
@@ -71,7 +81,202 @@ func TestNodeHTMLabel(t *testing.T) {
 	}
 }
 
+func TestGraphOptions(t *testing.T) {
+	tests := []struct {
+		opts []GraphOption
+		want string
+	}{
+		{
+			[]GraphOption{Vertical(true)},
+			`graph  {concentrate="true";fontname="Fira Code";fontsize="14";nodesep="0.8";orientation="portrait";pad="1";rankdir="TB";ranksep="2.3";splines="curved";}`,
+		},
+		{
+			[]GraphOption{Vertical(false), Radial(true)},
+			`graph  {concentrate="true";fontname="Fira Code";fontsize="14";layout="twopi";nodesep="0.8";orientation="portrait";overlap="false";pad="1";ranksep="2.3:1.8:1.4:1.2";splines="true";}`,
+		},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("test_%d", i), func(t *testing.T) {
+			gv := newGraph(tt.opts...)
+			if got := flatten(gv.String()); got != tt.want {
+				t.Errorf("got [%v] want [%v]", got, tt.want)
+			}
+		})
+	}
+}
+
 // remove tabs and newlines and spaces
 func flatten(s string) string {
 	return strings.Replace((strings.Replace(s, "\n", "", -1)), "\t", "", -1)
 }
+
+func TestTodoLabel(t *testing.T) {
+	tests := []struct {
+		line string
+		want string
+	}{
+		{
+			"* main idea",
+			`main idea`,
+		},
+		{
+			"* TODO main idea",
+			`<font color="#e63946"><b>TODO</b></font> main idea`,
+		},
+		{
+			"* TODO [#A] main idea",
+			`<font color="#e63946"><b>TODO [#A]</b></font> main idea`,
+		},
+		{
+			"* DONE main idea",
+			`<font color="#adb5bd"><b>DONE</b></font> <font color="#adb5bd"><s>main idea</s></font>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			note, err := crumbs.ParseLines([]string{tt.line}, crumbs.UseSyntax(crumbs.OrgSyntax))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			el := note.Childrens()[0]
+			if got := LightTheme().todoLabel(el, el.Text()); got != tt.want {
+				t.Errorf("got [%v] want [%v]", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLLabelNote(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* main idea",
+		"  a note & more",
+		"  on two lines",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<table border="0" cellborder="0">` +
+		`<tr><td><font point-size="14"><b>main idea</b></font></td></tr>` +
+		`<tr><td><font point-size="10" color="#6c757d">a note &amp; more<br/>on two lines</font></td></tr>` +
+		`</table>`
+
+	htmlize := htmlLabelMaker(0, LightTheme(), tagColorMaker(nil), nil)
+	if got := htmlize(note.Childrens()[0]); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestHTMLLabelEmoji(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{"* [[:rocket:]] launch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<table border="0" cellborder="0">` +
+		`<tr><td><font point-size="32">🚀</font></td></tr>` +
+		`<tr><td><font point-size="14"><b>launch</b></font></td></tr>` +
+		`</table>`
+
+	htmlize := htmlLabelMaker(0, LightTheme(), tagColorMaker(nil), nil)
+	if got := htmlize(note.Childrens()[0]); got != want {
+		t.Errorf("got [%v] want [%v]", got, want)
+	}
+}
+
+func TestRenderBundledIcons(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crumbs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	note, err := crumbs.ParseLines([]string{"* [[:bulb:]] idea"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	if err := Render(&sb, note, RenderConfig{IconsDir: dir}); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, sb.String(), `<img src="`+filepath.Join(dir, "bulb.svg")+`" />`)
+	assert.FileExists(t, filepath.Join(dir, "bulb.svg"))
+	assert.Equal(t, ":bulb:", note.Childrens()[0].Icon())
+}
+
+func TestRenderLinks(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* web app",
+		"** login -> #auth",
+		"** authentication {#auth}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	if err := Render(&sb, note, RenderConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `n2--n3[color="#adb5bd",constraint="false",dir="forward",penwidth="1.5",style="dashed"];`
+	assert.Contains(t, flatten(sb.String()), want)
+}
+
+func TestRenderAttrs(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* web app",
+		"** Risk {color=#e76f51 shape=box fillcolor=#ffcdb2 bold}",
+		"*** sub topic {fontsize=10 fontcolor=#264653}",
+		"** topic",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	if err := Render(&sb, note, RenderConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	got := flatten(sb.String())
+
+	// the node attributes
+	assert.Contains(t, got, `n2[fillcolor="#ffcdb2",fontname="Fira Code",fontsize="12",`+
+		`label=<<table border="0" cellborder="0"><tr><td><font point-size="12"><b>Risk</b></font></td></tr></table>>,`+
+		`margin="0.2,0.2",shape="box",style="filled",width="2"];`)
+	assert.Contains(t, got, `<font point-size="10"><font color="#264653">sub topic</font></font>`)
+	// the edge color is inherited
+	assert.Contains(t, got, `n1--n2[color="#e76f51"`)
+	assert.Contains(t, got, `n2--n3[color="#e76f51"`)
+	assert.Contains(t, got, `n1--n4[color="#E9C46A"`)
+}
+
+func TestRenderTags(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* web app",
+		"** login #backend #q3",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	err = Render(&sb, note, RenderConfig{
+		TagColors: map[string]string{"Backend": "#ff0000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := flatten(sb.String())
+
+	q3 := tagColorMaker(nil)("q3")
+	assert.Contains(t, got, `<tr><td><font point-size="10"><font color="#ff0000">#backend</font> `+
+		`<font color="`+q3+`">#q3</font></font></td></tr>`)
+	assert.Contains(t, got, `color="#ff0000"`)
+	assert.Contains(t, got, `shape="box",style="rounded"`)
+}
@@ -3,69 +3,208 @@ package gv
 import (
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/emicklei/dot"
 	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/palette"
 	"github.com/lucasepe/crumbs/text"
 )
 
 // RenderConfig defines some render parameters.
 type RenderConfig struct {
 	VerticalLayout bool
+	RadialLayout   bool
 	WrapTextLimit  uint
+	// TagColors maps the tags to the color of the tagged
+	// nodes border and badge; the tags not listed
+	// here get a color from a default palette.
+	TagColors map[string]string
+	// Theme defines colors, fonts and sizes
+	// (if nil the light theme is used).
+	Theme *Theme
+	// Coloring is the strategy used to color the edges
+	// (an explicit 'color' attribute always wins).
+	Coloring Coloring
+	// IconsDir is the folder where the bundled icons are
+	// written as files, since Graphviz can't read them
+	// otherwise (see crumbs.IconFile for the default).
+	IconsDir string
+}
+
+// renderer holds the render state.
+type renderer struct {
+	gr       *dot.Graph
+	theme    *Theme
+	htmlize  func(*crumbs.Entry) string
+	tagColor func(string) string
+	colorFor func(*crumbs.Entry) string
 }
 
 // Render translates the mind note tree to a
 // graphviz dot language definition.
+//
+// The bundled icons (i.e. ':bulb:') are written as
+// files in the RenderConfig.IconsDir folder.
 func Render(wr io.Writer, note *crumbs.Entry, cfg RenderConfig) error {
-	htmlize := htmlLabelMaker(cfg.WrapTextLimit)
+	th := cfg.Theme
+	if th == nil {
+		th = LightTheme()
+	}
+
+	icons := map[string]string{}
+	if err := iconFiles(note.Root(), cfg.IconsDir, icons); err != nil {
+		return err
+	}
+
+	r := &renderer{
+		gr:       newGraph(Themed(th), Vertical(cfg.VerticalLayout), Radial(cfg.RadialLayout)),
+		theme:    th,
+		tagColor: tagColorMaker(cfg.TagColors),
+	}
+	r.htmlize = htmlLabelMaker(cfg.WrapTextLimit, th, r.tagColor, icons)
+	r.colorFor = edgeColorMaker(cfg.Coloring, note, th, r.tagColor)
 
-	gr := newGraph(Vertical(cfg.VerticalLayout))
+	r.renderTree(note.Root())
+	r.renderLinks(note.Root())
 
-	renderTree(gr, note.Root(), htmlize)
+	if cfg.RadialLayout {
+		// pin the main ideas at the center
+		for _, el := range note.Root().Childrens() {
+			r.gr.Node(el.ID()).Attr("root", "true")
+		}
+	}
 
-	_, err := io.WriteString(wr, gr.String())
+	_, err := io.WriteString(wr, r.gr.String())
 	return err
 }
 
-// render a tree node (the node, and its children)
-func renderTree(gr *dot.Graph, el *crumbs.Entry, htmlize func(*crumbs.Entry) string) {
-	tintFor := colorSupplier()
+// iconFiles maps the bundled icons of the entry
+// and its descendants to their files (written in dir).
+func iconFiles(el *crumbs.Entry, dir string, icons map[string]string) error {
+	if icon := el.Icon(); crumbs.IsBundledIcon(icon) {
+		if _, ok := icons[icon]; !ok {
+			path, err := crumbs.IconFile(icon, dir)
+			if err != nil {
+				return err
+			}
+			icons[icon] = path
+		}
+	}
 
+	for _, child := range el.Childrens() {
+		if err := iconFiles(child, dir, icons); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// render a tree node (the node, and its children)
+func (r *renderer) renderTree(el *crumbs.Entry) {
 	if el.Level() > 0 {
-		createNode(gr, el.ID(), nodeLabel(htmlize(el), true))
+		createNode(r.gr, el.ID(), r.nodeStyle(el, nodeTheme(r.theme), nodeLabel(r.htmlize(el), true))...)
 	}
 
 	if el.Parent() != nil {
-		createEdge(gr, el.Parent().ID(), el.ID(), tintFor(el.Level()))
+		color := inheritedAttr(el, "color")
+		if color == "" {
+			color = r.colorFor(el)
+		}
+		createEdge(r.gr, el.Parent().ID(), el.ID(), color,
+			edgeFontName(r.theme.Node.FontName), edgePenWidth(r.theme.Edge.PenWidth))
 	}
 
 	for _, child := range el.Childrens() {
-		renderTree(gr, child, htmlize)
+		r.renderTree(child)
 	}
 }
 
-func colorSupplier() func(lvl int) string {
-	palette := map[int]string{
-		0: "#264653",
-		1: "#2A9D8F",
-		2: "#E9C46A",
-		3: "#E76F51",
-		4: "#FFCDB2",
-		5: "#B5838D",
-		6: "#6D6875",
+// nodeStyle appends to opts the node attributes set by the entry
+// tags (a border colored as the first tag) and by the entry
+// attributes (fillcolor, shape, fontsize).
+func (r *renderer) nodeStyle(el *crumbs.Entry, opts ...nodeAttribute) []nodeAttribute {
+	if tags := el.Tags(); len(tags) > 0 {
+		opts = append(opts, nodeBorder(r.tagColor(tags[0])))
+	}
+	if val := el.Attr("fillcolor"); val != "" {
+		opts = append(opts, nodeFillColor(val))
 	}
+	if val := el.Attr("shape"); val != "" {
+		opts = append(opts, nodeShape(val))
+	}
+	if val, err := strconv.Atoi(el.Attr("fontsize")); err == nil && val > 0 {
+		opts = append(opts, nodeFontSize(val))
+	}
+	return opts
+}
 
-	return func(lvl int) string {
-		if val, ok := palette[lvl]; ok {
+// inheritedAttr returns the value of the named attribute
+// set on the entry or on its nearest ancestor.
+func inheritedAttr(el *crumbs.Entry, key string) string {
+	for ; el != nil; el = el.Parent() {
+		if val := el.Attr(key); val != "" {
 			return val
 		}
-		return "#000000"
 	}
+	return ""
 }
 
-func htmlLabelMaker(lim uint) func(*crumbs.Entry) string {
+// tagColorMaker returns a function that supplies the color
+// of a tag, looking first in colors (ignoring the case).
+func tagColorMaker(colors map[string]string) func(string) string {
+	byKey := palette.ByKey()
+
+	return func(tag string) string {
+		for k, v := range colors {
+			if strings.EqualFold(k, tag) {
+				return v
+			}
+		}
+		return byKey(tag)
+	}
+}
+
+// renderLinks adds the cross-links between the tree nodes.
+func (r *renderer) renderLinks(el *crumbs.Entry) {
+	for _, target := range el.Links() {
+		createLink(r.gr, el.ID(), target.ID(),
+			edgeColor(r.theme.Link.Color), edgePenWidth(r.theme.Link.PenWidth))
+	}
+
+	for _, child := range el.Childrens() {
+		r.renderLinks(child)
+	}
+}
+
+// todoLabel prefixes the label with the TODO keyword
+// and the priority (if any); the done entries are grayed out.
+func (t *Theme) todoLabel(note *crumbs.Entry, label string) string {
+	var badge []string
+	if kw := note.Todo(); kw != "" {
+		badge = append(badge, kw)
+	}
+	if pri := note.Priority(); pri != "" {
+		badge = append(badge, "[#"+pri+"]")
+	}
+	if len(badge) == 0 {
+		return label
+	}
+
+	color := t.Colors.Todo
+	if note.Done() {
+		color = t.Colors.Done
+		label = fmt.Sprintf(`<font color="%s"><s>%s</s></font>`, t.Colors.Done, label)
+	}
+
+	return fmt.Sprintf(`<font color="%s"><b>%s</b></font> %s`, color, strings.Join(badge, " "), label)
+}
+
+// htmlLabelMaker returns a function that supplies the entry
+// HTML label; the icons found in the icons map are replaced.
+func htmlLabelMaker(lim uint, th *Theme, tagColor func(string) string, icons map[string]string) func(*crumbs.Entry) string {
 	escaper := strings.NewReplacer(
 		`&`, "&amp;",
 		`'`, "&#39;",
@@ -79,21 +218,63 @@ func htmlLabelMaker(lim uint) func(*crumbs.Entry) string {
 		}
 		label = escaper.Replace(label)
 		label = strings.ReplaceAll(label, "\n", "<br/>")
+		label = th.todoLabel(note, label)
 
 		var sb strings.Builder
 		sb.WriteString(`<table border="0" cellborder="0">`)
 
-		if len(note.Icon()) > 0 {
+		if icon := note.Icon(); crumbs.IsEmoji(icon) {
+			fmt.Fprintf(&sb, `<tr><td><font point-size="32">%s</font></td></tr>`, icon)
+		} else if len(icon) > 0 {
+			if path, ok := icons[icon]; ok {
+				icon = path
+			}
 			sb.WriteString("<tr>")
-			fmt.Fprintf(&sb, `<td fixedsize="true" width="48" height="48"><img src="%s" /></td>`, note.Icon())
+			fmt.Fprintf(&sb, `<td fixedsize="true" width="48" height="48"><img src="%s" /></td>`, icon)
 			sb.WriteString("</tr>")
 		}
 
-		switch {
-		case note.Level() == 1:
-			fmt.Fprintf(&sb, `<tr><td><font point-size="14"><b>%s</b></font></td></tr>`, label)
-		case note.Level() > 1:
-			fmt.Fprintf(&sb, `<tr><td><font point-size="12">%s</font></td></tr>`, label)
+		if note.Level() > 0 {
+			// the main ideas are usually bigger and bold
+			style := th.level(note.Level())
+			size, bold := style.FontSize, style.Bold || note.Attr("bold") == "true"
+			if val, err := strconv.Atoi(note.Attr("fontsize")); err == nil && val > 0 {
+				size = val
+			}
+
+			if bold {
+				label = "<b>" + label + "</b>"
+			}
+			if note.Attr("placeholder") == "true" {
+				// i.e. '+N more' (pruned entries)
+				label = fmt.Sprintf(`<font color="%s"><i>%s</i></font>`, th.Colors.Note, label)
+			}
+			if val := note.Attr("fontcolor"); val != "" {
+				label = fmt.Sprintf(`<font color="%s">%s</font>`, escaper.Replace(val), label)
+			} else if style.FontColor != "" {
+				label = fmt.Sprintf(`<font color="%s">%s</font>`, style.FontColor, label)
+			}
+			fmt.Fprintf(&sb, `<tr><td><font point-size="%d">%s</font></td></tr>`, size, label)
+		}
+
+		// the tags badge
+		if tags := note.Tags(); len(tags) > 0 && note.Level() > 0 {
+			badges := make([]string, len(tags))
+			for i, tag := range tags {
+				badges[i] = fmt.Sprintf(`<font color="%s">#%s</font>`,
+					escaper.Replace(tagColor(tag)), escaper.Replace(tag))
+			}
+			fmt.Fprintf(&sb, `<tr><td><font point-size="10">%s</font></td></tr>`, strings.Join(badges, " "))
+		}
+
+		// the note in a smaller font below the text
+		if body := strings.TrimSpace(note.Note()); body != "" && note.Level() > 0 {
+			if lim > 0 {
+				body = text.WrapString(body, lim)
+			}
+			body = escaper.Replace(body)
+			body = strings.ReplaceAll(body, "\n", "<br/>")
+			fmt.Fprintf(&sb, `<tr><td><font point-size="10" color="%s">%s</font></td></tr>`, th.Colors.Note, body)
 		}
 
 		sb.WriteString("</table>")
@@ -0,0 +1,196 @@
+package gv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Theme defines the look of the rendered graph.
+type Theme struct {
+	Graph GraphStyle `json:"graph"`
+	Node  NodeStyle  `json:"node"`
+	Edge  EdgeStyle  `json:"edge"`
+	// Link is the style of the cross-links.
+	Link LinkStyle `json:"link"`
+	// Levels are the label styles by depth: the first one is
+	// for the main ideas, the last one for all the deeper levels.
+	Levels []LabelStyle `json:"levels"`
+	Colors TextColors   `json:"colors"`
+}
+
+// GraphStyle defines the graph attributes.
+type GraphStyle struct {
+	Background string  `json:"background,omitempty"`
+	FontName   string  `json:"fontname"`
+	FontSize   int     `json:"fontsize"`
+	RankSep    float64 `json:"ranksep"`
+	NodeSep    float64 `json:"nodesep"`
+	Pad        float64 `json:"pad"`
+}
+
+// NodeStyle defines the nodes attributes.
+type NodeStyle struct {
+	FontName  string  `json:"fontname"`
+	FontSize  int     `json:"fontsize"`
+	FontColor string  `json:"fontcolor,omitempty"`
+	Width     float64 `json:"width"`
+	Margin    string  `json:"margin"`
+}
+
+// EdgeStyle defines the attributes of the edges
+// between the parent and the children nodes.
+type EdgeStyle struct {
+	PenWidth float64 `json:"penwidth"`
+	// Colors are the edge colors by depth (the first one
+	// for the edges to the main ideas); Color is used
+	// for the levels deeper than the colors.
+	Colors []string `json:"colors"`
+	Color  string   `json:"color"`
+}
+
+// LinkStyle defines the cross-links attributes.
+type LinkStyle struct {
+	PenWidth float64 `json:"penwidth"`
+	Color    string  `json:"color"`
+}
+
+// LabelStyle defines the text style of the labels of a level.
+type LabelStyle struct {
+	FontSize  int    `json:"fontsize"`
+	FontColor string `json:"fontcolor,omitempty"`
+	Bold      bool   `json:"bold,omitempty"`
+}
+
+// TextColors are the colors of some label parts.
+type TextColors struct {
+	// Todo is the color of the TODO keywords.
+	Todo string `json:"todo"`
+	// Done is the color of the entries done.
+	Done string `json:"done"`
+	// Note is the color of the notes and of the placeholders.
+	Note string `json:"note"`
+}
+
+// themes are the built-in themes.
+var themes = map[string]func() *Theme{
+	"light":         LightTheme,
+	"dark":          DarkTheme,
+	"monochrome":    MonochromeTheme,
+	"high-contrast": HighContrastTheme,
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	res := make([]string, 0, len(themes))
+	for k := range themes {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// ThemeByName returns the built-in theme with the given name.
+func ThemeByName(name string) (*Theme, error) {
+	fn, ok := themes[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return nil, fmt.Errorf("unknown theme '%s' (valid values are: %s)",
+			name, strings.Join(ThemeNames(), ", "))
+	}
+	return fn(), nil
+}
+
+// LoadTheme reads a JSON theme definition: the missing
+// properties are taken from the light theme.
+func LoadTheme(r io.Reader) (*Theme, error) {
+	res := LightTheme()
+	if err := json.NewDecoder(r).Decode(res); err != nil {
+		return nil, fmt.Errorf("theme: %w", err)
+	}
+	return res, nil
+}
+
+// LightTheme is the default theme.
+func LightTheme() *Theme {
+	return &Theme{
+		Graph: GraphStyle{FontName: "Fira Code", FontSize: 14, RankSep: 2.3, NodeSep: 0.8, Pad: 1},
+		Node:  NodeStyle{FontName: "Fira Code", FontSize: 12, Width: 2, Margin: "0.2,0.2"},
+		Edge: EdgeStyle{
+			PenWidth: 2.5,
+			Colors:   []string{"#2A9D8F", "#E9C46A", "#E76F51", "#FFCDB2", "#B5838D", "#6D6875"},
+			Color:    "#000000",
+		},
+		Link:   LinkStyle{PenWidth: 1.5, Color: "#adb5bd"},
+		Levels: []LabelStyle{{FontSize: 14, Bold: true}, {FontSize: 12}},
+		Colors: TextColors{Todo: "#e63946", Done: "#adb5bd", Note: "#6c757d"},
+	}
+}
+
+// DarkTheme has light text and bright edges on a dark background.
+func DarkTheme() *Theme {
+	res := LightTheme()
+	res.Graph.Background = "#1e1e2e"
+	res.Node.FontColor = "#e0e0e0"
+	res.Edge.Colors = []string{"#2A9D8F", "#E9C46A", "#F4A261", "#E76F51", "#B5838D", "#8AB17D"}
+	res.Edge.Color = "#adb5bd"
+	res.Link.Color = "#6c757d"
+	res.Colors = TextColors{Todo: "#ff6b6b", Done: "#6c757d", Note: "#adb5bd"}
+	return res
+}
+
+// MonochromeTheme uses only shades of gray, for printing.
+func MonochromeTheme() *Theme {
+	res := LightTheme()
+	res.Node.FontColor = "#000000"
+	res.Edge.PenWidth = 2
+	res.Edge.Colors = []string{"#000000", "#333333", "#555555", "#777777"}
+	res.Edge.Color = "#999999"
+	res.Link.Color = "#999999"
+	res.Colors = TextColors{Todo: "#000000", Done: "#999999", Note: "#555555"}
+	return res
+}
+
+// HighContrastTheme has bigger fonts, thicker
+// edges and saturated colors on a black background.
+func HighContrastTheme() *Theme {
+	res := LightTheme()
+	res.Graph.Background = "#000000"
+	res.Node.FontColor = "#ffffff"
+	res.Node.FontSize = 14
+	res.Edge.PenWidth = 4
+	res.Edge.Colors = []string{"#ffff00", "#00ffff", "#ff00ff", "#00ff00"}
+	res.Edge.Color = "#ffffff"
+	res.Link = LinkStyle{PenWidth: 2.5, Color: "#ffffff"}
+	res.Levels = []LabelStyle{{FontSize: 18, Bold: true}, {FontSize: 16, Bold: true}, {FontSize: 14}}
+	res.Colors = TextColors{Todo: "#ff4040", Done: "#a0a0a0", Note: "#ffff00"}
+	return res
+}
+
+// level returns the label style for the depth level.
+func (t *Theme) level(lvl int) LabelStyle {
+	switch {
+	case len(t.Levels) == 0:
+		return LabelStyle{FontSize: t.Node.FontSize}
+	case lvl < 1:
+		return t.Levels[0]
+	case lvl > len(t.Levels):
+		return t.Levels[len(t.Levels)-1]
+	}
+	return t.Levels[lvl-1]
+}
+
+// edgeColor returns the color of the edges to the nodes of the depth level.
+func (t *Theme) edgeColor(lvl int) string {
+	if lvl >= 1 && lvl <= len(t.Edge.Colors) {
+		return t.Edge.Colors[lvl-1]
+	}
+	return t.Edge.Color
+}
+
+// ftoa formats a float attribute value.
+func ftoa(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,78 @@
+package gv
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemeByName(t *testing.T) {
+	assert.Equal(t, []string{"dark", "high-contrast", "light", "monochrome"}, ThemeNames())
+
+	for _, name := range ThemeNames() {
+		th, err := ThemeByName(name)
+		if assert.NoError(t, err, name) {
+			assert.NotEmpty(t, th.Levels, name)
+			assert.NotEmpty(t, th.Edge.Colors, name)
+		}
+	}
+
+	_, err := ThemeByName("rainbow")
+	assert.EqualError(t, err, "unknown theme 'rainbow' (valid values are: dark, high-contrast, light, monochrome)")
+}
+
+func TestLoadTheme(t *testing.T) {
+	src := `{
+		"graph": {"background": "#222222"},
+		"edge": {"colors": ["#ff0000"]},
+		"levels": [{"fontsize": 20, "bold": true}]
+	}`
+
+	got, err := LoadTheme(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "#222222", got.Graph.Background)
+	// the missing properties come from the light theme
+	assert.Equal(t, "Fira Code", got.Graph.FontName)
+	assert.Equal(t, 2.5, got.Edge.PenWidth)
+	assert.Equal(t, "#ff0000", got.edgeColor(1))
+	assert.Equal(t, "#000000", got.edgeColor(2))
+	assert.Equal(t, 20, got.level(3).FontSize)
+
+	_, err = LoadTheme(strings.NewReader(`{"graph": []}`))
+	assert.Error(t, err)
+}
+
+func TestRenderTheme(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* main idea",
+		"** topic",
+		"*** sub topic",
+		"**** deep topic",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	th, err := ThemeByName("high-contrast")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	if err := Render(&sb, note, RenderConfig{Theme: th}); err != nil {
+		t.Fatal(err)
+	}
+	got := flatten(sb.String())
+
+	assert.Contains(t, got, `bgcolor="#000000"`)
+	assert.Contains(t, got, `fontcolor="#ffffff"`)
+	assert.Contains(t, got, `<font point-size="18"><b>main idea</b></font>`)
+	assert.Contains(t, got, `<font point-size="16"><b>topic</b></font>`)
+	assert.Contains(t, got, `<font point-size="14">deep topic</font>`)
+	assert.Contains(t, got, `n1--n2[color="#00ffff",fontname="Fira Code",fontsize="10",penwidth="4"];`)
+}
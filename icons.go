@@ -0,0 +1,357 @@
+package crumbs
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// IconResolver turns the name found in an icon marker
+// (i.e. 'bulb' for [[bulb]]) into the icon path (or glyph).
+type IconResolver interface {
+	Resolve(name string) (string, error)
+}
+
+// IconResolverFunc adapts an ordinary function to the IconResolver interface.
+type IconResolverFunc func(name string) (string, error)
+
+// Resolve calls f(name).
+func (f IconResolverFunc) Resolve(name string) (string, error) {
+	return f(name)
+}
+
+// ErrUnknownIcon matches (errors.Is) the errors returned
+// by the resolvers for the icon names they don't know.
+var ErrUnknownIcon = errors.New("no such icon")
+
+// UnknownIconError is the error returned by the resolvers for
+// the icon names they don't know; Hint (if any) tells the valid ones.
+type UnknownIconError struct {
+	Name string
+	Hint string
+}
+
+// Error implements the error interface.
+func (e *UnknownIconError) Error() string {
+	if e.Hint == "" {
+		return fmt.Sprintf("%s '%s'", ErrUnknownIcon, e.Name)
+	}
+	return fmt.Sprintf("%s '%s' (%s)", ErrUnknownIcon, e.Name, e.Hint)
+}
+
+// Is reports whether target is ErrUnknownIcon.
+func (e *UnknownIconError) Is(target error) bool {
+	return target == ErrUnknownIcon
+}
+
+// ChainIcons returns a resolver that tries the resolvers in order
+// and stops at the first one that knows the icon name; if none
+// does, the error tells the hints of all the resolvers.
+func ChainIcons(resolvers ...IconResolver) IconResolver {
+	return IconResolverFunc(func(name string) (string, error) {
+		var hints []string
+		for _, r := range resolvers {
+			res, err := r.Resolve(name)
+			if !errors.Is(err, ErrUnknownIcon) {
+				return res, err
+			}
+
+			var uerr *UnknownIconError
+			if errors.As(err, &uerr) && uerr.Hint != "" {
+				hints = append(hints, uerr.Hint)
+			}
+		}
+		return "", &UnknownIconError{Name: name, Hint: strings.Join(hints, "; ")}
+	})
+}
+
+// DirIcons resolves the icon names as files in the
+// dir folder with the ext extension (if not empty).
+// The shortcodes (i.e. ':bulb:') are left to the other resolvers.
+func DirIcons(dir, ext string) IconResolver {
+	return IconResolverFunc(func(name string) (string, error) {
+		if _, ok := shortcode(name); ok {
+			return "", &UnknownIconError{Name: name}
+		}
+
+		res := filepath.Join(dir, name)
+		if len(ext) > 0 {
+			res = fmt.Sprintf("%s.%s", res, ext)
+		}
+		return res, nil
+	})
+}
+
+// BundledIcons resolves the shortcodes of the bundled icons
+// (i.e. ':bulb:') keeping them as the icon name: the renderers
+// turn them into images, see IconDataURI and IconFile.
+func BundledIcons() IconResolver {
+	return IconResolverFunc(func(name string) (string, error) {
+		code, ok := shortcode(name)
+		if _, found := bundledIcons[code]; !ok || !found {
+			return "", &UnknownIconError{
+				Name: name,
+				Hint: "bundled icons are: " + strings.Join(BundledIconNames(), ", "),
+			}
+		}
+
+		return ":" + code + ":", nil
+	})
+}
+
+// IsBundledIcon reports whether the icon is
+// the shortcode of a bundled icon (i.e. ':bulb:').
+func IsBundledIcon(icon string) bool {
+	code, ok := shortcode(icon)
+	_, found := bundledIcons[code]
+	return ok && found
+}
+
+// BundledIconNames returns the sorted names of the bundled icons.
+func BundledIconNames() []string {
+	res := make([]string, 0, len(bundledIcons))
+	for k := range bundledIcons {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// EmojiIcons resolves the emoji shortcodes (i.e. ':rocket:')
+// into the emoji itself; see IsEmoji.
+func EmojiIcons() IconResolver {
+	return IconResolverFunc(func(name string) (string, error) {
+		code, ok := shortcode(name)
+		res, found := emojis[code]
+		if !ok || !found {
+			return "", &UnknownIconError{Name: name, Hint: "not an emoji shortcode"}
+		}
+		return res, nil
+	})
+}
+
+// IsEmoji reports whether the icon is an emoji
+// (a short text glyph) rather than an image path.
+func IsEmoji(icon string) bool {
+	if icon == "" || utf8.RuneCountInString(icon) > 8 {
+		return false
+	}
+
+	for _, r := range icon {
+		if r < utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// shortcode returns the name between the colons
+// of a shortcode (i.e. 'bulb' for ':bulb:').
+func shortcode(name string) (string, bool) {
+	if len(name) < 3 || !strings.HasPrefix(name, ":") || !strings.HasSuffix(name, ":") {
+		return "", false
+	}
+	return strings.ToLower(name[1 : len(name)-1]), true
+}
+
+// IconFile returns the path of the icon file, for the renderers
+// (like Graphviz) that can't read data URIs: the bundled icons are
+// written as files in the dir folder (created if needed, by default
+// the 'crumbs/icons' folder of the user cache directory) unless they
+// are already there, the other icons are returned as they are.
+func IconFile(icon, dir string) (string, error) {
+	code, ok := shortcode(icon)
+	src, found := bundledIcons[code]
+	if !ok || !found {
+		return icon, nil
+	}
+
+	if dir == "" {
+		cache, err := os.UserCacheDir()
+		if err != nil {
+			cache = os.TempDir()
+		}
+		dir = filepath.Join(cache, "crumbs", "icons")
+	}
+
+	path, err := writeIcon(filepath.Join(dir, code+".svg"), []byte(src))
+	if err != nil {
+		return "", fmt.Errorf("cannot write icon '%s': %w", icon, err)
+	}
+	return path, nil
+}
+
+// dataURI returns the (base64 encoded) data URI.
+func dataURI(mime string, src []byte) string {
+	return "data:" + mime + ";base64," + base64.StdEncoding.EncodeToString(src)
+}
+
+// writeIcon writes the icon file, unless it's already there.
+func writeIcon(path string, src []byte) (string, error) {
+	if old, err := ioutil.ReadFile(path); err == nil && bytes.Equal(old, src) {
+		return path, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	if err := ioutil.WriteFile(path, src, 0644); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+var (
+	errIconNotClosed = errors.New("missing closing ']]'")
+	errIconNoName    = errors.New("missing icon name")
+)
+
+// lookForIcon returns a function that moves a leading
+// icon marker (i.e. [[bulb.png]]) from the note text to the note icon.
+func lookForIcon(icons IconResolver) func(note *Entry) error {
+	re := regexp.MustCompile(`^\[{2}(.*?)\]{2}`)
+
+	return func(note *Entry) error {
+		str := note.text
+		res := re.FindStringSubmatch(str)
+		if len(res) == 0 {
+			if strings.HasPrefix(str, "[[") {
+				return errIconNotClosed
+			}
+			return nil
+		}
+
+		name := strings.TrimSpace(res[1])
+		if name == "" {
+			return errIconNoName
+		}
+
+		// the marker is removed even if the icon
+		// can't be resolved (and so it's dropped)
+		note.text = strings.TrimSpace(re.ReplaceAllString(str, ""))
+
+		icon, err := icons.Resolve(name)
+		if err != nil {
+			return err
+		}
+		note.icon = icon
+
+		return nil
+	}
+}
+
+var (
+	// ErrMissingIcon is returned (wrapped) when the icon file does not exist.
+	ErrMissingIcon = errors.New("no such file")
+	// ErrBadIconFormat is returned (wrapped) when the icon file is not an image.
+	ErrBadIconFormat = errors.New("unsupported format")
+)
+
+// iconTypes are the image formats understood by Graphviz and the browsers.
+var iconTypes = []string{"image/png", "image/jpeg", "image/gif", "image/svg+xml"}
+
+// IconType checks that the icon file exists and is an image
+// (png, jpeg, gif or svg) and returns its MIME type.
+func IconType(path string) (string, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return "", fmt.Errorf("%s: %w", path, ErrMissingIcon)
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+
+	res := sniffIcon(head[:n])
+	for _, el := range iconTypes {
+		if res == el {
+			return res, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: %w '%s' (valid formats are: png, jpeg, gif, svg)", path, ErrBadIconFormat, res)
+}
+
+// sniffIcon returns the MIME type of the file content.
+func sniffIcon(head []byte) string {
+	res := http.DetectContentType(head)
+	if i := strings.IndexByte(res, ';'); i >= 0 {
+		res = res[:i]
+	}
+
+	if res == "text/xml" || res == "text/plain" {
+		if bytes.Contains(bytes.ToLower(head), []byte("<svg")) {
+			return "image/svg+xml"
+		}
+	}
+
+	return res
+}
+
+// IconDataURI returns the content of the icon file (or
+// of the bundled icon) as a (base64 encoded) data URI.
+func IconDataURI(path string) (string, error) {
+	if code, ok := shortcode(path); ok {
+		if src, found := bundledIcons[code]; found {
+			return dataURI("image/svg+xml", []byte(src)), nil
+		}
+	}
+
+	mime, err := IconType(path)
+	if err != nil {
+		return "", err
+	}
+
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return dataURI(mime, src), nil
+}
+
+// checkedIcons wraps the resolver so that the icon files
+// (but not the emojis and the bundled icons) are checked with IconType.
+func checkedIcons(icons IconResolver) IconResolver {
+	return IconResolverFunc(func(name string) (string, error) {
+		res, err := icons.Resolve(name)
+		if err != nil || IsEmoji(res) || IsBundledIcon(res) {
+			return res, err
+		}
+
+		if _, err := IconType(res); err != nil {
+			return "", err
+		}
+		return res, nil
+	})
+}
+
+// iconErrorKind returns the diagnostic kind of the icon error.
+func iconErrorKind(err error) ErrorKind {
+	switch {
+	case errors.Is(err, errIconNotClosed), errors.Is(err, errIconNoName):
+		return MalformedIcon
+	case errors.Is(err, ErrMissingIcon):
+		return MissingIcon
+	case errors.Is(err, ErrBadIconFormat):
+		return BadIconFormat
+	}
+	return UnknownIcon
+}
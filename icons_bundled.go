@@ -0,0 +1,54 @@
+package crumbs
+
+// bundledIcons are the SVG sources of the icons
+// resolved by BundledIcons (i.e. [[:bulb:]]).
+var bundledIcons = map[string]string{
+	"bulb":     `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><path d="M24 4a14 14 0 0 0-8 25.5V34h16v-4.5A14 14 0 0 0 24 4z" fill="#f4a261"/><rect x="17" y="36" width="14" height="4" rx="1" fill="#6c757d"/><rect x="19" y="41" width="10" height="3" rx="1" fill="#6c757d"/></svg>`,
+	"check":    `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><circle cx="24" cy="24" r="20" fill="#2a9d8f"/><path d="M14 25l7 7 13-15" fill="none" stroke="#ffffff" stroke-width="5" stroke-linecap="round" stroke-linejoin="round"/></svg>`,
+	"cross":    `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><circle cx="24" cy="24" r="20" fill="#e63946"/><path d="M16 16l16 16M32 16L16 32" stroke="#ffffff" stroke-width="5" stroke-linecap="round"/></svg>`,
+	"flag":     `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><rect x="10" y="4" width="4" height="40" rx="1" fill="#6c757d"/><path d="M14 6h24l-6 9 6 9H14z" fill="#e63946"/></svg>`,
+	"heart":    `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><path d="M24 42S4 29 4 16a10 10 0 0 1 20-2 10 10 0 0 1 20 2c0 13-20 26-20 26z" fill="#e63946"/></svg>`,
+	"info":     `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><circle cx="24" cy="24" r="20" fill="#457b9d"/><circle cx="24" cy="14" r="3" fill="#ffffff"/><rect x="21" y="20" width="6" height="16" rx="2" fill="#ffffff"/></svg>`,
+	"lock":     `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><path d="M15 20v-6a9 9 0 0 1 18 0v6" fill="none" stroke="#6c757d" stroke-width="4"/><rect x="9" y="20" width="30" height="24" rx="3" fill="#e9c46a"/><circle cx="24" cy="31" r="3" fill="#6c757d"/></svg>`,
+	"question": `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><circle cx="24" cy="24" r="20" fill="#8338ec"/><path d="M18 18a6 6 0 1 1 8 5.5c-1.5.7-2 1.5-2 3.5v1" fill="none" stroke="#ffffff" stroke-width="4" stroke-linecap="round"/><circle cx="24" cy="35" r="2.5" fill="#ffffff"/></svg>`,
+	"star":     `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><path d="M24 4l6 13 14 1.5-10.5 9.5 3 14L24 35l-12.5 7 3-14L4 18.5 18 17z" fill="#e9c46a"/></svg>`,
+	"warning":  `<svg xmlns="http://www.w3.org/2000/svg" width="48" height="48" viewBox="0 0 48 48"><path d="M24 5L2 43h44z" fill="#f4a261"/><rect x="21.5" y="17" width="5" height="14" rx="2" fill="#ffffff"/><circle cx="24" cy="36" r="2.5" fill="#ffffff"/></svg>`,
+}
+
+// emojis maps the shortcodes resolved by EmojiIcons to the emojis.
+var emojis = map[string]string{
+	"bug":          "🐛",
+	"bulb":         "💡",
+	"calendar":     "📅",
+	"check":        "✅",
+	"clock":        "🕒",
+	"construction": "🚧",
+	"cross":        "❌",
+	"dart":         "🎯",
+	"fire":         "🔥",
+	"flag":         "🚩",
+	"gear":         "⚙️",
+	"heart":        "❤️",
+	"hourglass":    "⌛",
+	"info":         "ℹ️",
+	"key":          "🔑",
+	"link":         "🔗",
+	"lock":         "🔒",
+	"mag":          "🔍",
+	"memo":         "📝",
+	"money":        "💰",
+	"package":      "📦",
+	"pushpin":      "📌",
+	"question":     "❓",
+	"rocket":       "🚀",
+	"smile":        "😄",
+	"sparkles":     "✨",
+	"star":         "⭐",
+	"tada":         "🎉",
+	"thinking":     "🤔",
+	"thumbsdown":   "👎",
+	"thumbsup":     "👍",
+	"warning":      "⚠️",
+	"wrench":       "🔧",
+	"zap":          "⚡",
+}
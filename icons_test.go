@@ -0,0 +1,223 @@
+package crumbs
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBundledIcons(t *testing.T) {
+	icons := BundledIcons()
+
+	got, err := icons.Resolve(":Bulb:")
+	if assert.NoError(t, err) {
+		assert.Equal(t, ":bulb:", got)
+		assert.True(t, IsBundledIcon(got))
+	}
+	assert.False(t, IsBundledIcon(":rocket:"))
+	assert.False(t, IsBundledIcon("bulb"))
+
+	_, err = icons.Resolve(":nope:")
+	assert.True(t, errors.Is(err, ErrUnknownIcon))
+	assert.EqualError(t, err, "no such icon ':nope:' (bundled icons are: "+
+		"bulb, check, cross, flag, heart, info, lock, question, star, warning)")
+
+	_, err = icons.Resolve("bulb")
+	assert.True(t, errors.Is(err, ErrUnknownIcon))
+}
+
+func TestEmojiIcons(t *testing.T) {
+	icons := EmojiIcons()
+
+	got, err := icons.Resolve(":rocket:")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "🚀", got)
+	}
+
+	_, err = icons.Resolve(":nope:")
+	assert.EqualError(t, err, "no such icon ':nope:' (not an emoji shortcode)")
+}
+
+func TestChainIcons(t *testing.T) {
+	icons := ChainIcons(DirIcons("png", "png"), BundledIcons(), EmojiIcons())
+
+	tests := []struct {
+		name string
+		want string
+		err  string
+	}{
+		{"bulb", filepath.Join("png", "bulb.png"), ""},
+		{":tada:", "🎉", ""},
+		{":nope:", "", "no such icon ':nope:' (bundled icons are: " +
+			"bulb, check, cross, flag, heart, info, lock, question, star, warning; not an emoji shortcode)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := icons.Resolve(tt.name)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIsEmoji(t *testing.T) {
+	tests := []struct {
+		icon string
+		want bool
+	}{
+		{"🚀", true},
+		{"⚠️", true},
+		{"", false},
+		{"bulb.png", false},
+		{"/icons/🚀.png", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.icon, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsEmoji(tt.icon))
+		})
+	}
+}
+
+func TestParseShortcodes(t *testing.T) {
+	var warns []*ParseError
+	got, err := ParseLines([]string{
+		"* [[:tada:]] release",
+		"** [[:nope:]] topic",
+	}, Warn(func(e *ParseError) {
+		warns = append(warns, e)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "🎉", got.Childrens()[0].Icon())
+	if assert.Equal(t, 1, len(warns)) {
+		assert.Equal(t, UnknownIcon, warns[0].Kind)
+		assert.Equal(t, 2, warns[0].Line)
+		assert.True(t, strings.HasPrefix(warns[0].Msg, "unknown icon: no such icon ':nope:'"), warns[0].Msg)
+	}
+}
+
+func TestIconType(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+		err  error
+	}{
+		{"testdata/png/bulb.png", "image/png", nil},
+		{"testdata/png/nope.png", "", ErrMissingIcon},
+		{"testdata/sample1.txt", "", ErrBadIconFormat},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := IconType(tt.path)
+			if tt.err != nil {
+				assert.True(t, errors.Is(err, tt.err), err)
+				return
+			}
+			if assert.NoError(t, err) {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestIconFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "crumbs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	note, err := ParseLines([]string{
+		"* [[:bulb:]] main idea",
+		"** [[testdata/png/bulb.png]] topic",
+	}, CheckIcons(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := note.Childrens()[0]
+	assert.Equal(t, ":bulb:", main.Icon(), "the entry keeps the icon name")
+
+	got, err := IconFile(main.Icon(), dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, filepath.Join(dir, "bulb.svg"), got)
+		mime, err := IconType(got)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "image/svg+xml", mime)
+		}
+	}
+	assert.Equal(t, ":bulb:", main.Icon())
+
+	got, err = IconFile(main.Childrens()[0].Icon(), dir)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "testdata/png/bulb.png", got)
+	}
+
+	// a folder that can't be created (bulb.svg is a file)
+	bad := filepath.Join(dir, "bulb.svg", "icons")
+	_, err = IconFile(":star:", bad)
+	assert.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "cannot write icon ':star:': "), err.Error())
+}
+
+func TestIconDataURI(t *testing.T) {
+	got, err := IconDataURI("testdata/png/bulb.png")
+	if assert.NoError(t, err) {
+		assert.True(t, strings.HasPrefix(got, "data:image/png;base64,iVBORw0KGgo"), got[:32])
+	}
+
+	got, err = IconDataURI(":bulb:")
+	if assert.NoError(t, err) {
+		assert.True(t, strings.HasPrefix(got, "data:image/svg+xml;base64,"), got[:32])
+	}
+
+	_, err = IconDataURI("testdata/sample1.txt")
+	assert.EqualError(t, err, "testdata/sample1.txt: unsupported format 'text/plain' (valid formats are: png, jpeg, gif, svg)")
+}
+
+func TestParseCheckIcons(t *testing.T) {
+	var warns []*ParseError
+	got, err := ParseLines([]string{
+		"* [[png/bulb.png]] main idea",
+		"** [[png/nope.png]] topic",
+		"** [[sample1.txt]] another topic",
+		"** [[:tada:]] done",
+	}, CheckIcons(true), Icons(ChainIcons(DirIcons("testdata", ""), EmojiIcons())),
+		Warn(func(e *ParseError) {
+			warns = append(warns, e)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := got.Childrens()[0]
+	assert.Equal(t, filepath.Join("testdata", "png", "bulb.png"), main.Icon())
+	assert.Equal(t, "", main.Childrens()[0].Icon())
+	assert.Equal(t, "topic", main.Childrens()[0].Text())
+	assert.Equal(t, "", main.Childrens()[1].Icon())
+	assert.Equal(t, "another topic", main.Childrens()[1].Text())
+	assert.Equal(t, "🎉", main.Childrens()[2].Icon())
+
+	if assert.Equal(t, 2, len(warns)) {
+		assert.Equal(t, MissingIcon, warns[0].Kind)
+		assert.Equal(t, 2, warns[0].Line)
+		assert.Equal(t, "missing icon: "+filepath.Join("testdata", "png", "nope.png")+": no such file", warns[0].Msg)
+		assert.Equal(t, BadIconFormat, warns[1].Kind)
+		assert.Equal(t, 3, warns[1].Line)
+	}
+}
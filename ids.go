@@ -0,0 +1,118 @@
+package crumbs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/teris-io/shortid"
+)
+
+// IDStrategy defines how the node identifiers are generated.
+type IDStrategy int
+
+const (
+	// PathIDs derives the identifier from the position of the
+	// node in the tree (i.e. 'n1.2.3' is the third child of the
+	// second child of the first level 1 node).
+	PathIDs IDStrategy = iota
+	// HashIDs derives the identifier hashing the node text
+	// together with the text of all its ancestors.
+	HashIDs
+	// RandomIDs generates a new random short id for each node.
+	RandomIDs
+)
+
+var idStrategyNames = map[IDStrategy]string{
+	PathIDs:   "path",
+	HashIDs:   "hash",
+	RandomIDs: "random",
+}
+
+// String returns the strategy name.
+func (s IDStrategy) String() string {
+	if name, ok := idStrategyNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("IDStrategy(%d)", int(s))
+}
+
+// Set sets the strategy by name, so that an IDStrategy
+// can be used as a command line flag value.
+func (s *IDStrategy) Set(name string) error {
+	for k, v := range idStrategyNames {
+		if strings.EqualFold(v, strings.TrimSpace(name)) {
+			*s = k
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown id strategy '%s' (valid values are: path, hash, random)", name)
+}
+
+// idGenerator returns a function that generates the identifier
+// of a note already attached to its parent (nil for the root).
+func idGenerator(s IDStrategy) func(note *Entry) (string, error) {
+	switch s {
+	case HashIDs:
+		return hashIDs()
+	case RandomIDs:
+		return randomIDs()
+	default:
+		return pathIDs()
+	}
+}
+
+// pathIDs generates identifiers like 'n1.2.3'.
+func pathIDs() func(note *Entry) (string, error) {
+	return func(note *Entry) (string, error) {
+		if note.parent == nil {
+			return "n", nil
+		}
+
+		idx := strconv.Itoa(len(note.parent.childrens))
+		if note.parent.parent == nil {
+			return note.parent.id + idx, nil
+		}
+
+		return note.parent.id + "." + idx, nil
+	}
+}
+
+// hashIDs generates identifiers hashing the parent
+// identifier (that covers all the ancestors) and the note text.
+// Siblings with the same text get a numeric suffix.
+func hashIDs() func(note *Entry) (string, error) {
+	seen := map[string]int{}
+
+	return func(note *Entry) (string, error) {
+		h := fnv.New64a()
+		if note.parent != nil {
+			h.Write([]byte(note.parent.id))
+		}
+		h.Write([]byte{0})
+		h.Write([]byte(note.text))
+
+		id := fmt.Sprintf("h%x", h.Sum64())
+		seen[id]++
+		if n := seen[id]; n > 1 {
+			id = fmt.Sprintf("%s-%d", id, n)
+		}
+
+		return id, nil
+	}
+}
+
+// randomIDs generates a new short id at each invocation.
+func randomIDs() func(note *Entry) (string, error) {
+	sid, err := shortid.New(1, shortid.DefaultABC, 2342)
+	if err != nil {
+		return func(*Entry) (string, error) {
+			return "", err
+		}
+	}
+
+	return func(*Entry) (string, error) {
+		return sid.Generate()
+	}
+}
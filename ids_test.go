@@ -0,0 +1,83 @@
+package crumbs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPathIDs(t *testing.T) {
+	test := `
+* main idea
+** topic 1
+*** sub topic 1 1
+*** sub topic 1 2
+** topic 2
+`
+	got, err := ParseLines(strings.SplitAfter(test, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "n", got.ID())
+	assert.Equal(t, "n1", got.childrens[0].ID())
+	assert.Equal(t, "n1.1", got.childrens[0].childrens[0].ID())
+	assert.Equal(t, "n1.1.2", got.childrens[0].childrens[0].childrens[1].ID())
+	assert.Equal(t, "n1.2", got.childrens[0].childrens[1].ID())
+}
+
+func TestHashIDs(t *testing.T) {
+	test := `
+* main idea
+** topic
+** topic
+** other
+*** topic
+`
+	lines := strings.SplitAfter(test, "\n")
+
+	first, err := ParseLines(lines, IDs(HashIDs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second, err := ParseLines(lines, IDs(HashIDs))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := map[string]bool{}
+	var walk func(a, b *Entry)
+	walk = func(a, b *Entry) {
+		assert.Equal(t, a.ID(), b.ID())
+		assert.False(t, seen[a.ID()], "duplicated id %s", a.ID())
+		seen[a.ID()] = true
+		for i := range a.childrens {
+			walk(a.childrens[i], b.childrens[i])
+		}
+	}
+	walk(first, second)
+}
+
+func TestIDStrategySet(t *testing.T) {
+	tests := []struct {
+		name string
+		want IDStrategy
+		err  bool
+	}{
+		{"path", PathIDs, false},
+		{"Hash", HashIDs, false},
+		{"random", RandomIDs, false},
+		{"uuid", PathIDs, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got IDStrategy
+			err := got.Set(tt.name)
+			assert.Equal(t, tt.err, err != nil)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
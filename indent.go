@@ -0,0 +1,61 @@
+package crumbs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// indentedLines returns a function that adds to the tree the
+// entries defined by indented text lines: the indentation
+// unit (a tab or N spaces) is taken from the first indented
+// line and the level is one more than the number of units.
+//
+// The inconsistent indentation (mixed tabs and spaces or not
+// a multiple of the unit) makes the parse fail, even when
+// not in strict mode, since the level can't be told.
+func indentedLines(p *parser) func(lineNo int, el string) error {
+	// the indentation unit: "\t" or N spaces
+	unit := ""
+
+	return func(lineNo int, el string) error {
+		el = strings.TrimRight(el, "\r\n")
+		if strings.TrimSpace(el) == "" {
+			return nil
+		}
+
+		text := strings.TrimLeft(el, " \t")
+		ws := el[:len(el)-len(text)]
+
+		if unit == "" && ws != "" {
+			if ws[0] == '\t' {
+				unit = "\t"
+			} else {
+				unit = ws[:len(ws)-len(strings.TrimLeft(ws, " "))]
+			}
+		}
+
+		level := 1
+		if ws != "" {
+			if strings.Trim(ws, unit[:1]) != "" || len(ws)%len(unit) != 0 {
+				p.fail(lineNo, 1, BadIndent, "%s: expected a multiple of %s", BadIndent, unitName(unit))
+				return nil
+			}
+			level += len(ws) / len(unit)
+		}
+
+		_, err := p.add(lineNo, len(ws)+1, level, strings.TrimSpace(text))
+		return err
+	}
+}
+
+// unitName describes the indentation unit.
+func unitName(unit string) string {
+	switch {
+	case unit == "\t":
+		return "tabs"
+	case len(unit) == 1:
+		return "1 space"
+	default:
+		return fmt.Sprintf("%d spaces", len(unit))
+	}
+}
@@ -0,0 +1,102 @@
+package crumbs
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseIndented(t *testing.T) {
+	f, err := os.Open("testdata/sample1.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	got, err := Parse(f, UseSyntax(IndentSyntax), Strict(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.True(t, len(got.childrens) > 1)
+
+	parent := got.childrens[0]
+	assert.Equal(t, "parent1", parent.text)
+	assert.Equal(t, 4, len(parent.childrens))
+	assert.Equal(t, "child3", parent.childrens[2].text)
+	assert.Equal(t, []string{"child3.1", "child3.2"},
+		[]string{parent.childrens[2].childrens[0].text, parent.childrens[2].childrens[1].text})
+	assert.Equal(t, 3, parent.childrens[2].childrens[1].level)
+}
+
+func TestParseIndentedSpaces(t *testing.T) {
+	test := `main idea
+  topic 1
+    [[bulb]] sub topic
+  topic 2
+`
+	got, err := ParseLines(strings.SplitAfter(test, "\n"), UseSyntax(IndentSyntax), Strict(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := got.childrens[0]
+	assert.Equal(t, 2, len(main.childrens))
+	assert.Equal(t, "sub topic", main.childrens[0].childrens[0].text)
+	assert.Equal(t, "bulb", main.childrens[0].childrens[0].icon)
+}
+
+func TestParseIndentedDiagnostics(t *testing.T) {
+	test := "main idea\n" +
+		"    topic 1\n" +
+		"      sub topic\n" +
+		"    \ttopic 2\n" +
+		"                too deep\n"
+
+	_, err := ParseLines(strings.SplitAfter(test, "\n"), UseSyntax(IndentSyntax), Strict(true))
+	errs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("got [%v] want ParseErrors", err)
+	}
+
+	want := []struct {
+		line int
+		kind ErrorKind
+	}{
+		{3, BadIndent},
+		{4, BadIndent},
+		{5, LevelJump},
+	}
+
+	if len(errs) != len(want) {
+		t.Fatalf("got [%v] want %d errors", errs, len(want))
+	}
+	for i, tt := range want {
+		assert.Equal(t, tt.line, errs[i].Line)
+		assert.Equal(t, tt.kind, errs[i].Kind)
+	}
+	assert.Equal(t, "3:1: inconsistent indentation: expected a multiple of 4 spaces", errs[0].Error())
+}
+
+func TestParseIndentedBadIndentLenient(t *testing.T) {
+	test := "a\n  b\n    c\n\t  d\n"
+
+	var warns []*ParseError
+	got, err := ParseLines(strings.SplitAfter(test, "\n"), UseSyntax(IndentSyntax),
+		Warn(func(e *ParseError) {
+			warns = append(warns, e)
+		}))
+	assert.Nil(t, got)
+
+	errs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("got [%v] want ParseErrors", err)
+	}
+	if assert.Equal(t, 1, len(errs)) {
+		assert.Equal(t, BadIndent, errs[0].Kind)
+		assert.Equal(t, "4:1: inconsistent indentation: expected a multiple of 2 spaces", errs[0].Error())
+	}
+	assert.Empty(t, warns)
+}
@@ -0,0 +1,59 @@
+package crumbs
+
+import (
+	"fmt"
+	"regexp"
+)
+
+var (
+	reAnchor = regexp.MustCompile(`[ \t]*\{#([\pL\pN_][\pL\pN_.:-]*)\}`)
+	reLink   = regexp.MustCompile(`[ \t]*->[ \t]*#([\pL\pN_][\pL\pN_.:-]*)`)
+)
+
+// lookForLinks moves the anchor (i.e. {#auth}) and the
+// link references (i.e. -> #auth) from the note text to the note.
+func lookForLinks(note *Entry) {
+	if res := reAnchor.FindStringSubmatch(note.text); res != nil {
+		note.anchor = res[1]
+		note.text = reAnchor.ReplaceAllString(note.text, "")
+	}
+
+	for _, res := range reLink.FindAllStringSubmatch(note.text, -1) {
+		note.refs = append(note.refs, res[1])
+	}
+	note.text = reLink.ReplaceAllString(note.text, "")
+}
+
+// resolveLinks links each entry of the tree to the entries
+// anchored as its references. The duplicate anchors and
+// the dangling references are notified to report (if not nil).
+func resolveLinks(root *Entry, report func(e *Entry, kind ErrorKind, msg string)) {
+	if report == nil {
+		report = func(*Entry, ErrorKind, string) {}
+	}
+
+	anchors := map[string]*Entry{}
+	root.Walk(func(e *Entry) {
+		if e.anchor == "" {
+			return
+		}
+		if prev, ok := anchors[e.anchor]; ok {
+			report(e, DuplicateAnchor, fmt.Sprintf("%s: '#%s' already defined at line %d",
+				DuplicateAnchor, e.anchor, prev.line))
+			return
+		}
+		anchors[e.anchor] = e
+	})
+
+	root.Walk(func(e *Entry) {
+		e.links = nil
+		for _, ref := range e.refs {
+			target, ok := anchors[ref]
+			if !ok {
+				report(e, DanglingLink, fmt.Sprintf("%s: no entry with anchor '#%s'", DanglingLink, ref))
+				continue
+			}
+			e.links = append(e.links, target)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package crumbs
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookForLinks(t *testing.T) {
+	tests := []struct {
+		text   string
+		want   string
+		anchor string
+		refs   []string
+	}{
+		{"Authentication {#auth}", "Authentication", "auth", nil},
+		{"Login -> #auth", "Login", "", []string{"auth"}},
+		{"Login {#login} ->#auth -> #db", "Login", "login", []string{"auth", "db"}},
+		{"a -> b", "a -> b", "", nil},
+		{"#auth {#}", "#auth {#}", "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			note := newNote(1, tt.text)
+			lookForLinks(note)
+			assert.Equal(t, tt.want, note.text)
+			assert.Equal(t, tt.anchor, note.Anchor())
+			assert.Equal(t, tt.refs, note.refs)
+		})
+	}
+}
+
+func TestParseLinks(t *testing.T) {
+	got, err := ParseLines([]string{
+		"* web app",
+		"** login -> #auth -> #db",
+		"** authentication {#auth}",
+		"*** database {#db}",
+	}, Strict(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := got.Childrens()[0]
+	login, auth := main.Childrens()[0], main.Childrens()[1]
+	assert.Equal(t, "login", login.Text())
+	assert.Equal(t, []*Entry{auth, auth.Childrens()[0]}, login.Links())
+	assert.Equal(t, 0, len(auth.Links()))
+
+	// the links survive a JSON roundtrip
+	src, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var back Entry
+	if err := json.Unmarshal(src, &back); err != nil {
+		t.Fatal(err)
+	}
+	login = back.Childrens()[0].Childrens()[0]
+	assert.Equal(t, 2, len(login.Links()))
+	assert.Equal(t, "n1.2.1", login.Links()[1].ID())
+}
+
+func TestParseLinksDiagnostics(t *testing.T) {
+	_, err := ParseLines([]string{
+		"* main idea {#main}",
+		"** topic {#main}",
+		"** topic -> #nowhere",
+	}, Strict(true))
+	errs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("got [%v] want ParseErrors", err)
+	}
+
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, DuplicateAnchor, errs[0].Kind)
+	assert.Equal(t, "2:1: duplicate anchor: '#main' already defined at line 1", errs[0].Error())
+	assert.Equal(t, DanglingLink, errs[1].Kind)
+	assert.Equal(t, 3, errs[1].Line)
+}
@@ -0,0 +1,103 @@
+package crumbs
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	reMarkdownHeading = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	reMarkdownItem    = regexp.MustCompile(`^([ \t]*)(?:[-*+]|\d{1,9}[.)])[ \t]+(.*)$`)
+	reMarkdownFence   = regexp.MustCompile("^ {0,3}(```|~~~)")
+	reMarkdownImage   = regexp.MustCompile(`!\[[^\]]*\]\(\s*([^)\s]+)(?:\s+"[^"]*")?\s*\)`)
+)
+
+// markdownLines returns a function that adds to the tree
+// the entries defined by the markdown headings (the level
+// is the number of '#', counted from the shallowest heading
+// seen so far: a document that starts at '##' has its '##'
+// headings as main ideas) and by the bullet list items (the
+// level is one more than the last heading, plus the nesting
+// depth). All the other lines are ignored.
+func markdownLines(p *parser) func(lineNo int, el string) error {
+	heading := 0
+	// number of '#' of the shallowest heading (zero if none yet)
+	shallowest := 0
+	// indentation columns of the open list items
+	var indents []int
+	fenced := false
+
+	return func(lineNo int, el string) error {
+		el = strings.TrimRight(el, "\r\n")
+
+		// skip the fenced code blocks
+		if reMarkdownFence.MatchString(el) {
+			fenced = !fenced
+			return nil
+		}
+		if fenced {
+			return nil
+		}
+
+		if res := reMarkdownHeading.FindStringSubmatchIndex(el); res != nil {
+			depth := res[3] - res[2]
+			if shallowest == 0 || depth < shallowest {
+				shallowest = depth
+			}
+			heading = depth - shallowest + 1
+			indents = indents[:0]
+
+			text, col := "", res[3]+1
+			if res[4] >= 0 {
+				text, col = el[res[4]:res[5]], res[4]+1
+			}
+			return p.addMarkdown(lineNo, col, heading, text)
+		}
+
+		if res := reMarkdownItem.FindStringSubmatchIndex(el); res != nil {
+			indent := columns(el[res[2]:res[3]])
+			for len(indents) > 0 && indent < indents[len(indents)-1] {
+				indents = indents[:len(indents)-1]
+			}
+			if len(indents) == 0 || indent > indents[len(indents)-1] {
+				indents = append(indents, indent)
+			}
+
+			return p.addMarkdown(lineNo, res[4]+1, heading+len(indents), el[res[4]:res[5]])
+		}
+
+		return nil
+	}
+}
+
+// addMarkdown adds the entry, taking the icon
+// from the first inline image (i.e. ![](bulb.png)).
+func (p *parser) addMarkdown(lineNo, col, level int, text string) error {
+	var icon string
+	if res := reMarkdownImage.FindStringSubmatchIndex(text); res != nil {
+		icon = text[res[2]:res[3]]
+		text = text[:res[0]] + text[res[1]:]
+	}
+
+	e, err := p.add(lineNo, col, level, strings.TrimSpace(text))
+	if err != nil {
+		return err
+	}
+	p.setIcon(e, icon)
+
+	return nil
+}
+
+// columns returns the width of the leading whitespace
+// (tabs stop every 4 columns).
+func columns(ws string) int {
+	res := 0
+	for _, r := range ws {
+		if r == '\t' {
+			res += 4 - res%4
+		} else {
+			res++
+		}
+	}
+	return res
+}
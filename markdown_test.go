@@ -0,0 +1,109 @@
+package crumbs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMarkdown(t *testing.T) {
+	test := "# ![bulb](png/bulb.png) main idea #\n" +
+		"\n" +
+		"Some introduction paragraph.\n" +
+		"\n" +
+		"## topic 1\n" +
+		"- sub topic\n" +
+		"- sub topic ![](png/comments-alt.png \"comments\")\n" +
+		"    * sub topic\n" +
+		"\t* sub topic\n" +
+		"  1. numbered sub topic\n" +
+		"- sub topic\n" +
+		"```\n" +
+		"# not a heading\n" +
+		"- not an item\n" +
+		"```\n" +
+		"## topic 2\n" +
+		"+ sub topic\n"
+
+	got, err := Parse(strings.NewReader(test), UseSyntax(MarkdownSyntax), Strict(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, len(got.childrens))
+
+	main := got.childrens[0]
+	assert.Equal(t, "main idea", main.text)
+	assert.Equal(t, "png/bulb.png", main.icon)
+	assert.Equal(t, 2, len(main.childrens))
+
+	topic := main.childrens[0]
+	assert.Equal(t, "topic 1", topic.text)
+	assert.Equal(t, 3, len(topic.childrens))
+	assert.Equal(t, "sub topic", topic.childrens[1].text)
+	assert.Equal(t, "png/comments-alt.png", topic.childrens[1].icon)
+	assert.Equal(t, 3, len(topic.childrens[1].childrens))
+	assert.Equal(t, 4, topic.childrens[1].childrens[0].level)
+	assert.Equal(t, "numbered sub topic", topic.childrens[1].childrens[2].text)
+
+	assert.Equal(t, "topic 2", main.childrens[1].text)
+	assert.Equal(t, 1, len(main.childrens[1].childrens))
+}
+
+func TestParseMarkdownHeadingLevels(t *testing.T) {
+	test := "## Install\n" +
+		"- go get\n" +
+		"### From source\n" +
+		"## Usage\n" +
+		"# Appendix\n" +
+		"## Credits\n"
+
+	var warns []*ParseError
+	got, err := ParseLines(strings.SplitAfter(test, "\n"), UseSyntax(MarkdownSyntax),
+		Warn(func(e *ParseError) {
+			warns = append(warns, e)
+		}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, warns)
+
+	var texts []string
+	for _, el := range got.childrens {
+		texts = append(texts, el.text)
+	}
+	assert.Equal(t, []string{"Install", "Usage", "Appendix"}, texts)
+
+	install := got.childrens[0]
+	assert.Equal(t, 1, install.level)
+	assert.Equal(t, 2, len(install.childrens))
+	assert.Equal(t, "go get", install.childrens[0].text)
+	assert.Equal(t, 2, install.childrens[0].level)
+	assert.Equal(t, "From source", install.childrens[1].text)
+	assert.Equal(t, 2, install.childrens[1].level)
+
+	// a shallower heading becomes the new main idea level
+	appendix := got.childrens[2]
+	assert.Equal(t, 1, appendix.level)
+	assert.Equal(t, "Credits", appendix.childrens[0].text)
+	assert.Equal(t, 2, appendix.childrens[0].level)
+}
+
+func TestParseMarkdownDiagnostics(t *testing.T) {
+	test := "# main idea\n" +
+		"#### too deep\n" +
+		"#\n"
+
+	_, err := ParseLines(strings.SplitAfter(test, "\n"), UseSyntax(MarkdownSyntax), Strict(true))
+	errs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("got [%v] want ParseErrors", err)
+	}
+
+	assert.Equal(t, 2, len(errs))
+	assert.Equal(t, LevelJump, errs[0].Kind)
+	assert.Equal(t, 2, errs[0].Line)
+	assert.Equal(t, EmptyHeading, errs[1].Kind)
+	assert.Equal(t, 3, errs[1].Line)
+}
@@ -0,0 +1,160 @@
+package crumbs
+
+import (
+	"encoding/json"
+	"strconv"
+)
+
+// entryData is the serializable form of an entry, without its children.
+type entryData struct {
+	ID         string            `json:"id,omitempty" yaml:"id,omitempty"`
+	Level      int               `json:"level" yaml:"level"`
+	Text       string            `json:"text,omitempty" yaml:"text,omitempty"`
+	Note       string            `json:"note,omitempty" yaml:"note,omitempty"`
+	Icon       string            `json:"icon,omitempty" yaml:"icon,omitempty"`
+	Anchor     string            `json:"anchor,omitempty" yaml:"anchor,omitempty"`
+	Links      []string          `json:"links,omitempty" yaml:"links,omitempty"`
+	Todo       string            `json:"todo,omitempty" yaml:"todo,omitempty"`
+	Done       bool              `json:"done,omitempty" yaml:"done,omitempty"`
+	Priority   string            `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Tags       []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Attrs      map[string]string `json:"attrs,omitempty" yaml:"attrs,omitempty"`
+	Properties map[string]string `json:"properties,omitempty" yaml:"properties,omitempty"`
+}
+
+// entryDoc is the serializable form of an entry and its children.
+type entryDoc struct {
+	entryData `yaml:",inline"`
+	Children  []*Entry `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// entryNode is the decoded form of an entry and its children,
+// turned into entries once the whole tree has been read.
+type entryNode struct {
+	entryData `yaml:",inline"`
+	Children  []*entryNode `json:"children,omitempty" yaml:"children,omitempty"`
+}
+
+// data returns the serializable form of the entry.
+func (ti *Entry) data() entryDoc {
+	d := entryData{
+		ID:         ti.id,
+		Level:      ti.level,
+		Text:       ti.text,
+		Note:       ti.note,
+		Icon:       ti.icon,
+		Anchor:     ti.anchor,
+		Links:      ti.refs,
+		Todo:       ti.todo,
+		Done:       ti.done,
+		Priority:   ti.priority,
+		Tags:       ti.tags,
+		Attrs:      ti.attrs,
+		Properties: ti.props,
+	}
+
+	return entryDoc{entryData: d, Children: ti.childrens}
+}
+
+// setData sets the entry fields and creates the children.
+// The levels are taken from the nesting, not from the decoded
+// values: an entry without a level (or with a negative one) is the
+// root, and each child is one level below its parent. When the entry
+// is the root, the entries without an identifier get one derived
+// from their position and the links are resolved.
+func (ti *Entry) setData(n *entryNode) {
+	level := n.Level
+	if level <= 0 {
+		level = -1
+	}
+	ti.fromNode(n, level)
+
+	if ti.level < 0 {
+		fillIDs(ti)
+		resolveLinks(ti, nil)
+	}
+}
+
+// fromNode sets the entry fields, with the given level,
+// and links the children (one level below) to the entry.
+func (ti *Entry) fromNode(n *entryNode, level int) {
+	d := n.entryData
+	*ti = Entry{
+		id:       d.ID,
+		level:    level,
+		text:     d.Text,
+		note:     d.Note,
+		icon:     d.Icon,
+		anchor:   d.Anchor,
+		refs:     d.Links,
+		todo:     d.Todo,
+		done:     d.Done,
+		priority: d.Priority,
+		tags:     d.Tags,
+		attrs:    d.Attrs,
+		props:    d.Properties,
+	}
+
+	if level < 0 {
+		level = 0
+	}
+	for _, el := range n.Children {
+		child := &Entry{}
+		child.fromNode(el, level+1)
+		child.parent = ti
+		ti.childrens = append(ti.childrens, child)
+	}
+}
+
+// fillIDs sets the missing identifiers as the PathIDs strategy does.
+func fillIDs(note *Entry) {
+	if note.id == "" && note.parent == nil {
+		note.id = "n"
+	}
+
+	for i, el := range note.childrens {
+		if el.id == "" {
+			idx := strconv.Itoa(i + 1)
+			if note.parent == nil {
+				el.id = note.id + idx
+			} else {
+				el.id = note.id + "." + idx
+			}
+		}
+		fillIDs(el)
+	}
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (ti *Entry) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ti.data())
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface,
+// linking each entry to its parent.
+func (ti *Entry) UnmarshalJSON(b []byte) error {
+	var n entryNode
+	if err := json.Unmarshal(b, &n); err != nil {
+		return err
+	}
+
+	ti.setData(&n)
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface.
+func (ti *Entry) MarshalYAML() (interface{}, error) {
+	return ti.data(), nil
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler
+// interface, linking each entry to its parent.
+func (ti *Entry) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var n entryNode
+	if err := unmarshal(&n); err != nil {
+		return err
+	}
+
+	ti.setData(&n)
+	return nil
+}
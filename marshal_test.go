@@ -0,0 +1,124 @@
+package crumbs
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	note, err := ParseLines([]string{
+		"* TODO [#A] main idea :work:",
+		"** [[bulb]] topic",
+	}, UseSyntax(OrgSyntax))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := json.Marshal(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"id":"n","level":-1,"children":[` +
+		`{"id":"n1","level":1,"text":"main idea","todo":"TODO","priority":"A","tags":["work"],"children":[` +
+		`{"id":"n1.1","level":2,"text":"topic","icon":"bulb"}]}]}`
+	assert.Equal(t, want, string(got))
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	src := `{"level":-1,"children":[
+		{"level":1,"text":"main idea","attrs":{"color":"#ff0000"},"children":[
+			{"id":"custom","level":2,"text":"topic 1","note":"first line\nsecond line"},
+			{"level":2,"text":"topic 2","properties":{"OWNER":"Alice"}}]}]}`
+
+	var got Entry
+	if err := json.Unmarshal([]byte(src), &got); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "n", got.ID())
+	assert.Nil(t, got.Parent())
+
+	main := got.Childrens()[0]
+	assert.Equal(t, "n1", main.ID())
+	assert.Equal(t, &got, main.Parent())
+	assert.Equal(t, "#ff0000", main.Attr("color"))
+
+	topics := main.Childrens()
+	assert.Equal(t, "custom", topics[0].ID())
+	assert.Equal(t, "first line\nsecond line", topics[0].Note())
+	assert.Equal(t, "n1.2", topics[1].ID())
+	assert.Equal(t, "Alice", topics[1].Property("OWNER"))
+	assert.Equal(t, main, topics[1].Parent())
+	assert.Equal(t, &got, topics[1].Root())
+}
+
+func TestUnmarshalJSONLevels(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+	}{
+		{"missing", `{"children":[{"text":"main","children":[{"text":"a"}]}]}`},
+		{"inconsistent", `{"level":-1,"children":[{"level":3,"text":"main","children":[{"level":1,"text":"a"}]}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Entry
+			if err := json.Unmarshal([]byte(tt.src), &got); err != nil {
+				t.Fatal(err)
+			}
+
+			assert.Equal(t, -1, got.Level())
+			assert.Equal(t, "n", got.ID())
+
+			main := got.Childrens()[0]
+			assert.Equal(t, 1, main.Level())
+			assert.Equal(t, "n1", main.ID())
+
+			sub := main.Childrens()[0]
+			assert.Equal(t, 2, sub.Level())
+			assert.Equal(t, "n1.1", sub.ID())
+			assert.Equal(t, main, sub.Parent())
+		})
+	}
+}
+
+func TestYAMLRoundtrip(t *testing.T) {
+	note, err := Parse(strings.NewReader("* main idea\n** topic 1\n*** sub topic\n** topic 2\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, err := yaml.Marshal(note)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Entry
+	if err := yaml.Unmarshal(src, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	back, err := yaml.Marshal(&got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, string(src), string(back))
+
+	sub := got.Childrens()[0].Childrens()[0].Childrens()[0]
+	assert.Equal(t, "sub topic", sub.Text())
+	assert.Equal(t, "n1.1.1", sub.ID())
+	assert.Equal(t, 3, sub.Level())
+	assert.Equal(t, "topic 1", sub.Parent().Text())
+}
+
+func TestUnmarshalYAMLMalformed(t *testing.T) {
+	var got Entry
+	err := yaml.Unmarshal([]byte("0: [:!00 \xef"), &got)
+	assert.Error(t, err)
+}
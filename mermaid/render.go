@@ -0,0 +1,108 @@
+package mermaid
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/text"
+)
+
+// RenderConfig defines some render parameters.
+type RenderConfig struct {
+	// Fenced wraps the mindmap in a ```mermaid code block.
+	Fenced bool
+	// IconClass maps the entry icon to the CSS class of
+	// an icon font (an empty string means no icon).
+	// If nil FontAwesomeIcons is used.
+	IconClass func(icon string) string
+}
+
+// FontAwesomeIcons maps the icon file name (without extension)
+// to a Font Awesome icon, i.e. 'png/map-signs.png' to 'fa fa-map-signs'.
+func FontAwesomeIcons(icon string) string {
+	name := strings.TrimSuffix(filepath.Base(icon), filepath.Ext(icon))
+	if name == "" || name == "." {
+		return ""
+	}
+	return "fa fa-" + name
+}
+
+// Render translates the mind note tree to a
+// mermaid mindmap definition.
+func Render(wr io.Writer, note *crumbs.Entry, cfg RenderConfig) error {
+	roots := note.Root().Childrens()
+	if len(roots) > 1 {
+		return fmt.Errorf("mermaid mindmap must have only one main idea, found %d", len(roots))
+	}
+
+	if cfg.IconClass == nil {
+		cfg.IconClass = FontAwesomeIcons
+	}
+
+	bw := bufio.NewWriter(wr)
+	if cfg.Fenced {
+		fmt.Fprintln(bw, "```mermaid")
+	}
+	fmt.Fprintln(bw, "mindmap")
+
+	for _, el := range roots {
+		renderTree(bw, el, 1, cfg)
+	}
+
+	if cfg.Fenced {
+		fmt.Fprintln(bw, "```")
+	}
+
+	return bw.Flush()
+}
+
+// render a tree node (the node, and its children)
+func renderTree(wr io.Writer, el *crumbs.Entry, depth int, cfg RenderConfig) {
+	indent := strings.Repeat("  ", depth)
+
+	label := escape(el.Text())
+	if icon := el.Icon(); crumbs.IsEmoji(icon) {
+		label = icon + " " + label
+	}
+	if depth == 1 {
+		fmt.Fprintf(wr, "%sroot((%s))\n", indent, label)
+	} else {
+		fmt.Fprintf(wr, "%s%s\n", indent, label)
+	}
+
+	if icon := el.Icon(); len(icon) > 0 && !crumbs.IsEmoji(icon) && !crumbs.IsBundledIcon(icon) {
+		if class := cfg.IconClass(el.Icon()); class != "" {
+			fmt.Fprintf(wr, "%s::icon(%s)\n", indent, class)
+		}
+	}
+
+	for _, child := range el.Childrens() {
+		renderTree(wr, child, depth+1, cfg)
+	}
+}
+
+// escaper replaces the characters that have a
+// meaning in the mermaid syntax with entity codes.
+var escaper = strings.NewReplacer(
+	`#`, "#35;",
+	`"`, "#quot;",
+	`(`, "#40;",
+	`)`, "#41;",
+	`[`, "#91;",
+	`]`, "#93;",
+	`{`, "#123;",
+	`}`, "#125;",
+)
+
+// escape turns the entry text into a mermaid node label.
+func escape(str string) string {
+	lines := strings.Split(text.StripTags(strings.TrimSpace(str)), "\n")
+	for i, s := range lines {
+		lines[i] = escaper.Replace(strings.TrimSpace(s))
+	}
+	return strings.Join(lines, "<br/>")
+}
@@ -0,0 +1,61 @@
+package mermaid
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	test := `
+* [[png/bulb.png]] main idea
+** topic (1)
+*** sub topic: #1
+*** sub <b>topic</b><br/>on two lines
+** [[png/map-signs.png]] topic 2
+`
+	want := "mindmap\n" +
+		"  root((main idea))\n" +
+		"  ::icon(fa fa-bulb)\n" +
+		"    topic #40;1#41;\n" +
+		"      sub topic: #35;1\n" +
+		"      sub topic<br/>on two lines\n" +
+		"    topic 2\n" +
+		"    ::icon(fa fa-map-signs)\n"
+
+	note, err := crumbs.ParseLines(strings.SplitAfter(test, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, note, RenderConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, buf.String())
+
+	buf.Reset()
+	err = Render(&buf, note, RenderConfig{
+		Fenced:    true,
+		IconClass: func(string) string { return "" },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.True(t, strings.HasPrefix(buf.String(), "```mermaid\nmindmap\n"))
+	assert.True(t, strings.HasSuffix(buf.String(), "```\n"))
+	assert.NotContains(t, buf.String(), "::icon")
+}
+
+func TestRenderManyRoots(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{"* one", "* two"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Render(&bytes.Buffer{}, note, RenderConfig{})
+	assert.Error(t, err)
+}
@@ -5,9 +5,20 @@ type Entry struct {
 	id        string
 	level     int
 	text      string
+	note      string
 	icon      string
+	anchor    string
+	refs      []string
+	links     []*Entry
 	parent    *Entry
 	childrens []*Entry
+	line      int
+	attrs     map[string]string
+	todo      string
+	done      bool
+	priority  string
+	tags      []string
+	props     map[string]string
 }
 
 // ID returns the node identifier.
@@ -25,11 +36,76 @@ func (ti *Entry) Text() string {
 	return ti.text
 }
 
+// Note returns the (multi-line) note attached to the node.
+func (ti *Entry) Note() string {
+	return ti.note
+}
+
 // Icon returns the icon path.
 func (ti *Entry) Icon() string {
 	return ti.icon
 }
 
+// Anchor returns the name other nodes use to link to this node.
+func (ti *Entry) Anchor() string {
+	return ti.anchor
+}
+
+// Links returns the nodes linked to this node (not its children).
+func (ti *Entry) Links() []*Entry {
+	return ti.links
+}
+
+// Attr returns the value of the named
+// attribute (i.e. 'color'), empty if not set.
+func (ti *Entry) Attr(key string) string {
+	return ti.attrs[key]
+}
+
+// Attrs returns a copy of all the node attributes.
+func (ti *Entry) Attrs() map[string]string {
+	res := make(map[string]string, len(ti.attrs))
+	for k, v := range ti.attrs {
+		res[k] = v
+	}
+	return res
+}
+
+// Todo returns the TODO keyword (i.e. 'TODO', 'DONE'), empty if not set.
+func (ti *Entry) Todo() string {
+	return ti.todo
+}
+
+// Done reports whether the TODO keyword is a done state.
+func (ti *Entry) Done() bool {
+	return ti.done
+}
+
+// Priority returns the priority cookie (i.e. 'A'), empty if not set.
+func (ti *Entry) Priority() string {
+	return ti.priority
+}
+
+// Tags returns the node tags.
+func (ti *Entry) Tags() []string {
+	return ti.tags
+}
+
+// Property returns the value of the named
+// property (from the org-mode PROPERTIES drawer).
+func (ti *Entry) Property(key string) string {
+	return ti.props[key]
+}
+
+// Properties returns a copy of all the node properties.
+func (ti *Entry) Properties() map[string]string {
+	res := make(map[string]string, len(ti.props))
+	for k, v := range ti.props {
+		res[k] = v
+	}
+	return res
+}
+
 // Level returns the node depth.
 func (ti *Entry) Level() int {
 	return ti.level
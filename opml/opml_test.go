@@ -0,0 +1,164 @@
+package opml
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+const sample = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head>
+    <title>main idea</title>
+  </head>
+  <body>
+    <outline text="main idea" icon="png/bulb.png">
+      <outline text="topic 1" _note="first line&#xA;second line">
+        <outline text="sub topic &amp; co"></outline>
+        <outline text="sub topic"></outline>
+      </outline>
+      <outline text="topic 2"></outline>
+    </outline>
+  </body>
+</opml>
+`
+
+func TestRead(t *testing.T) {
+	got, err := Read(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, len(got.Childrens()))
+
+	main := got.Childrens()[0]
+	assert.Equal(t, "main idea", main.Text())
+	assert.Equal(t, "png/bulb.png", main.Icon())
+	assert.Equal(t, 2, len(main.Childrens()))
+	assert.Equal(t, "first line\nsecond line", main.Childrens()[0].Note())
+	assert.Equal(t, "topic 2", main.Childrens()[1].Text())
+	assert.Equal(t, "sub topic & co", main.Childrens()[0].Childrens()[0].Text())
+	assert.Equal(t, 3, main.Childrens()[0].Childrens()[0].Level())
+}
+
+func TestReadDiagnostics(t *testing.T) {
+	src := `<opml version="2.0"><body>
+<outline text="main idea">
+<outline text=""/>
+</outline>
+</body></opml>`
+
+	_, err := Read(strings.NewReader(src), crumbs.Strict(true))
+	errs, ok := err.(crumbs.ParseErrors)
+	if !ok {
+		t.Fatalf("got [%v] want crumbs.ParseErrors", err)
+	}
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, crumbs.EmptyHeading, errs[0].Kind)
+	assert.Equal(t, 3, errs[0].Line)
+
+	_, err = Read(strings.NewReader(`<opml><body><outline text="x"></body></opml>`))
+	assert.Error(t, err)
+}
+
+func TestReadKeepsText(t *testing.T) {
+	src := `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head>
+    <title>Meeting notes {draft}</title>
+  </head>
+  <body>
+    <outline text="Meeting notes {draft}">
+      <outline text="Budget for #q3 review #todo"></outline>
+      <outline text="Call -&gt; #alice"></outline>
+    </outline>
+  </body>
+</opml>
+`
+
+	var warns []*crumbs.ParseError
+	got, err := Read(strings.NewReader(src), crumbs.Warn(func(e *crumbs.ParseError) {
+		warns = append(warns, e)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Empty(t, warns)
+
+	main := got.Childrens()[0]
+	assert.Equal(t, "Meeting notes {draft}", main.Text())
+	assert.Empty(t, main.Attrs())
+	assert.Equal(t, "Budget for #q3 review #todo", main.Childrens()[0].Text())
+	assert.Empty(t, main.Childrens()[0].Tags())
+	assert.Equal(t, "Call -> #alice", main.Childrens()[1].Text())
+	assert.Empty(t, main.Childrens()[1].Links())
+
+	var buf bytes.Buffer
+	if err := Write(&buf, got); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, src, buf.String())
+}
+
+func TestBundledIcons(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{"* [[:bulb:]] main idea"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, note); err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, buf.String(), `<outline text="main idea" icon=":bulb:">`)
+
+	got, err := Read(&buf, crumbs.ImagesPath("icons"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, ":bulb:", got.Childrens()[0].Icon())
+}
+
+func TestReadMaxSize(t *testing.T) {
+	_, err := Read(strings.NewReader(sample), crumbs.MaxSize(int64(len(sample))-1))
+	assert.True(t, errors.Is(err, crumbs.ErrTooLarge), err)
+
+	_, err = Read(strings.NewReader(sample), crumbs.MaxSize(int64(len(sample))))
+	assert.NoError(t, err)
+}
+
+func TestWrite(t *testing.T) {
+	note, err := Read(strings.NewReader(sample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, note); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, sample, buf.String())
+}
+
+func TestWriteFromText(t *testing.T) {
+	test := `
+* [[bulb.png]] main idea
+** topic "1"
+`
+	note, err := crumbs.ParseLines(strings.SplitAfter(test, "\n"), crumbs.ImagesPath("icons"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, note); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Contains(t, buf.String(), `<outline text="main idea" icon="icons/bulb.png">`)
+	assert.Contains(t, buf.String(), `<outline text="topic &#34;1&#34;"></outline>`)
+}
@@ -0,0 +1,79 @@
+package opml
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/text"
+)
+
+// Read builds the entry tree from an OPML document: each
+// <outline> element is an entry, the 'text' attribute is
+// the entry text, the 'icon' attribute is the entry icon
+// and the '_note' attribute is the entry note.
+func Read(r io.Reader, opts ...crumbs.ParseOption) (*crumbs.Entry, error) {
+	b, err := crumbs.NewBuilder(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := ioutil.ReadAll(b.Limit(r))
+	if err != nil {
+		return nil, err
+	}
+
+	lines := text.LineCounter(src)
+
+	dec := xml.NewDecoder(bytes.NewReader(src))
+	inBody, depth := false, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("opml: line %d: %w", lines(dec.InputOffset()), err)
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case el.Name.Local == "body":
+				inBody = true
+			case el.Name.Local == "outline" && inBody:
+				depth++
+
+				e, err := b.Add(lines(dec.InputOffset()), depth, attr(el, "text"))
+				if err != nil {
+					return nil, err
+				}
+				b.SetIcon(e, attr(el, "icon"))
+				b.SetNote(e, attr(el, "_note"))
+			}
+
+		case xml.EndElement:
+			switch {
+			case el.Name.Local == "body":
+				inBody = false
+			case el.Name.Local == "outline" && inBody:
+				depth--
+			}
+		}
+	}
+
+	return b.Tree()
+}
+
+// attr returns the value of the named attribute.
+func attr(el xml.StartElement, name string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,64 @@
+package opml
+
+import (
+	"encoding/xml"
+	"io"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+)
+
+type document struct {
+	XMLName xml.Name  `xml:"opml"`
+	Version string    `xml:"version,attr"`
+	Title   string    `xml:"head>title,omitempty"`
+	Body    []outline `xml:"body>outline"`
+}
+
+type outline struct {
+	Text     string    `xml:"text,attr"`
+	Icon     string    `xml:"icon,attr,omitempty"`
+	Note     string    `xml:"_note,attr,omitempty"`
+	Outlines []outline `xml:"outline"`
+}
+
+// Write serializes the entry tree as an OPML 2.0 document,
+// titled as the first main idea.
+func Write(wr io.Writer, note *crumbs.Entry) error {
+	doc := document{Version: "2.0"}
+
+	for _, el := range note.Root().Childrens() {
+		if doc.Title == "" {
+			doc.Title = strings.TrimSpace(el.Text())
+		}
+		doc.Body = append(doc.Body, newOutline(el))
+	}
+
+	if _, err := io.WriteString(wr, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(wr)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(wr, "\n")
+	return err
+}
+
+// newOutline creates the outline element for the entry (and its children).
+func newOutline(el *crumbs.Entry) outline {
+	res := outline{
+		Text: strings.TrimSpace(el.Text()),
+		Icon: el.Icon(),
+		Note: el.Note(),
+	}
+
+	for _, child := range el.Childrens() {
+		res.Outlines = append(res.Outlines, newOutline(child))
+	}
+
+	return res
+}
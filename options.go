@@ -0,0 +1,143 @@
+package crumbs
+
+// Syntax is the text lines syntax.
+type Syntax int
+
+const (
+	// CrumbsSyntax is the asterisk-indented text lines syntax.
+	CrumbsSyntax Syntax = iota
+	// MarkdownSyntax takes the entries from the markdown
+	// headings and (nested) bullet lists.
+	MarkdownSyntax
+	// IndentSyntax takes the entry level from the leading
+	// whitespace (tabs or a fixed number of spaces).
+	IndentSyntax
+	// OrgSyntax is the org-mode headlines syntax, with
+	// TODO keywords, priorities, tags and properties.
+	OrgSyntax
+)
+
+// ParseOptions defines some parse parameters.
+type ParseOptions struct {
+	// Syntax is the text lines syntax.
+	Syntax Syntax
+	// ImagesPath is the folder in which to look for image files.
+	ImagesPath string
+	// ImagesSuffix is the default extension of the image files.
+	ImagesSuffix string
+	// IDs defines how the node identifiers are generated.
+	IDs IDStrategy
+	// Bullet is the character that marks the entry level.
+	Bullet rune
+	// Strict turns every diagnostic into an error.
+	Strict bool
+	// Warn, if not nil, is called for each diagnostic
+	// found when not in strict mode.
+	Warn func(*ParseError)
+	// MaxSize is the maximum input size in bytes
+	// read by Parse (zero means no limit).
+	MaxSize int64
+	// Icons resolves the icon markers; if nil the names are
+	// looked for in ImagesPath (with the ImagesSuffix extension),
+	// among the bundled icons and the emoji shortcodes.
+	Icons IconResolver
+	// CheckIcons reports the icon files that
+	// don't exist or are not images.
+	CheckIcons bool
+}
+
+// ParseOption is a parser option.
+type ParseOption func(*ParseOptions)
+
+// newParseOptions applies the options over the defaults.
+func newParseOptions(opts ...ParseOption) ParseOptions {
+	res := ParseOptions{
+		IDs:    PathIDs,
+		Bullet: '*',
+	}
+
+	for _, opt := range opts {
+		opt(&res)
+	}
+
+	if res.Icons == nil {
+		res.Icons = ChainIcons(
+			DirIcons(res.ImagesPath, res.ImagesSuffix),
+			BundledIcons(),
+			EmojiIcons(),
+		)
+	}
+
+	return res
+}
+
+// UseSyntax sets the text lines syntax.
+func UseSyntax(s Syntax) ParseOption {
+	return func(o *ParseOptions) {
+		o.Syntax = s
+	}
+}
+
+// ImagesPath sets the folder in which to look for image files.
+func ImagesPath(path string) ParseOption {
+	return func(o *ParseOptions) {
+		o.ImagesPath = path
+	}
+}
+
+// ImagesSuffix sets the default extension of the image files.
+func ImagesSuffix(suffix string) ParseOption {
+	return func(o *ParseOptions) {
+		o.ImagesSuffix = suffix
+	}
+}
+
+// IDs sets how the node identifiers are generated.
+func IDs(s IDStrategy) ParseOption {
+	return func(o *ParseOptions) {
+		o.IDs = s
+	}
+}
+
+// Bullet sets the character that marks the entry level (default '*').
+func Bullet(marker rune) ParseOption {
+	return func(o *ParseOptions) {
+		o.Bullet = marker
+	}
+}
+
+// Strict enables/disables the strict parse mode.
+func Strict(set bool) ParseOption {
+	return func(o *ParseOptions) {
+		o.Strict = set
+	}
+}
+
+// Warn sets the function called for each
+// diagnostic found when not in strict mode.
+func Warn(fn func(*ParseError)) ParseOption {
+	return func(o *ParseOptions) {
+		o.Warn = fn
+	}
+}
+
+// MaxSize sets the maximum input size in bytes (zero means no limit).
+func MaxSize(n int64) ParseOption {
+	return func(o *ParseOptions) {
+		o.MaxSize = n
+	}
+}
+
+// CheckIcons enables/disables the icon files check.
+func CheckIcons(set bool) ParseOption {
+	return func(o *ParseOptions) {
+		o.CheckIcons = set
+	}
+}
+
+// Icons sets the resolver of the icon markers.
+func Icons(r IconResolver) ParseOption {
+	return func(o *ParseOptions) {
+		o.Icons = r
+	}
+}
@@ -0,0 +1,146 @@
+package crumbs
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	reOrgHeadline = regexp.MustCompile(`^(\*+)(?:[ \t]+(.*?))?[ \t]*$`)
+	reOrgPriority = regexp.MustCompile(`^\[#([A-Z0-9])\](?:[ \t]+|$)`)
+	reOrgTags     = regexp.MustCompile(`(?:^|[ \t]+)(:(?:[^\s:]+:)+)$`)
+	reOrgDrawer   = regexp.MustCompile(`^[ \t]*:([\w-]+):[ \t]*$`)
+	reOrgProperty = regexp.MustCompile(`^[ \t]*:([^\s:]+):(?:[ \t]+(.*?))?[ \t]*$`)
+	reOrgTodoKeys = regexp.MustCompile(`(?i)^#\+(?:SEQ_|TYP_)?TODO:(.*)$`)
+	// keywords, comments and planning lines
+	reOrgSkip = regexp.MustCompile(`^(?:#(?:\+|\s|$)|(?:SCHEDULED|DEADLINE|CLOSED):)`)
+)
+
+// orgLines returns a function that adds to the tree the
+// entries defined by the org-mode headlines, capturing their
+// TODO keyword, priority, tags and PROPERTIES drawer.
+// The body text is the note of the last headline,
+// all the other lines are ignored.
+func orgLines(p *parser) func(lineNo int, el string) error {
+	todo := map[string]bool{"TODO": false, "DONE": true}
+	// the current drawer name (empty if outside a drawer)
+	drawer := ""
+
+	return func(lineNo int, el string) error {
+		el = strings.TrimRight(el, "\r\n")
+
+		if res := reOrgTodoKeys.FindStringSubmatch(el); res != nil {
+			todo = orgTodoKeywords(res[1])
+			return nil
+		}
+
+		if res := reOrgHeadline.FindStringSubmatchIndex(el); res != nil {
+			drawer = ""
+
+			text, col := "", res[3]+1
+			if res[4] >= 0 {
+				text, col = el[res[4]:res[5]], res[4]+1
+			}
+			return p.addOrg(lineNo, col, res[3]-res[2], text, todo)
+		}
+
+		if drawer != "" {
+			if strings.EqualFold(strings.TrimSpace(el), ":END:") {
+				drawer = ""
+				return nil
+			}
+
+			if drawer == "PROPERTIES" {
+				if res := reOrgProperty.FindStringSubmatch(el); res != nil {
+					if p.node.props == nil {
+						p.node.props = map[string]string{}
+					}
+					p.node.props[res[1]] = res[2]
+				}
+			}
+			return nil
+		}
+
+		if res := reOrgDrawer.FindStringSubmatch(el); res != nil {
+			drawer = strings.ToUpper(res[1])
+			return nil
+		}
+
+		line := strings.TrimSpace(el)
+		if line != "" && p.node != p.root && !reOrgSkip.MatchString(line) {
+			appendNote(p.node, line)
+		}
+
+		return nil
+	}
+}
+
+// addOrg adds the entry defined by the headline text,
+// splitting out the TODO keyword, the priority and the tags.
+func (p *parser) addOrg(lineNo, col, level int, text string, todo map[string]bool) error {
+	var keyword, priority string
+	var tags []string
+
+	if fields := strings.Fields(text); len(fields) > 0 {
+		if _, ok := todo[fields[0]]; ok {
+			keyword = fields[0]
+			text = strings.TrimLeft(text[len(keyword):], " \t")
+		}
+	}
+
+	if res := reOrgPriority.FindStringSubmatch(text); res != nil {
+		priority = res[1]
+		text = text[len(res[0]):]
+	}
+
+	if res := reOrgTags.FindStringSubmatchIndex(text); res != nil {
+		tags = strings.Split(strings.Trim(text[res[2]:res[3]], ":"), ":")
+		text = text[:res[0]]
+	}
+
+	e, err := p.add(lineNo, col, level, strings.TrimSpace(text))
+	if err != nil {
+		return err
+	}
+
+	e.todo, e.done = keyword, todo[keyword]
+	e.priority = priority
+	for _, tag := range tags {
+		e.addTag(tag)
+	}
+
+	return nil
+}
+
+// orgTodoKeywords parses the keywords of a '#+TODO:' line
+// (i.e. 'TODO NEXT | DONE CANCELED'): the ones after the bar
+// are the done states; without the bar only the last one is.
+// The fast access keys (i.e. 'TODO(t)') are discarded.
+func orgTodoKeywords(line string) map[string]bool {
+	res := map[string]bool{}
+
+	fields := strings.Fields(line)
+	done := false
+	bar := false
+	for _, el := range fields {
+		if el == "|" {
+			done, bar = true, true
+			continue
+		}
+
+		if i := strings.IndexByte(el, '('); i > 0 {
+			el = el[:i]
+		}
+		res[el] = done
+	}
+
+	if !bar && len(fields) > 0 {
+		last := fields[len(fields)-1]
+		if i := strings.IndexByte(last, '('); i > 0 {
+			last = last[:i]
+		}
+		res[last] = true
+	}
+
+	return res
+}
@@ -0,0 +1,96 @@
+package crumbs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOrg(t *testing.T) {
+	test := "#+TITLE: Project\n" +
+		"Some introduction paragraph.\n" +
+		"* TODO [#A] [[bulb]] main idea :work:urgent:\n" +
+		"  SCHEDULED: <2026-10-20 Tue>\n" +
+		"  :PROPERTIES:\n" +
+		"  :OWNER: Alice\n" +
+		"  :EFFORT:\n" +
+		"  :END:\n" +
+		"  body text\n" +
+		"  # a comment\n" +
+		"  more body text\n" +
+		"** DONE topic 1\n" +
+		"  :LOGBOOK:\n" +
+		"  :OWNER: nobody\n" +
+		"  :END:\n" +
+		"** topic 2 :idea:\n" +
+		"*not a headline*\n"
+
+	got, err := Parse(strings.NewReader(test), UseSyntax(OrgSyntax), Strict(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, len(got.childrens))
+
+	main := got.childrens[0]
+	assert.Equal(t, "main idea", main.text)
+	assert.Equal(t, "bulb", main.icon)
+	assert.Equal(t, "TODO", main.Todo())
+	assert.False(t, main.Done())
+	assert.Equal(t, "A", main.Priority())
+	assert.Equal(t, []string{"work", "urgent"}, main.Tags())
+	assert.Equal(t, map[string]string{"OWNER": "Alice", "EFFORT": ""}, main.Properties())
+	assert.Equal(t, "body text\nmore body text", main.Note())
+	assert.Equal(t, 2, len(main.childrens))
+
+	topic := main.childrens[0]
+	assert.Equal(t, "topic 1", topic.text)
+	assert.Equal(t, "DONE", topic.Todo())
+	assert.True(t, topic.Done())
+	assert.Equal(t, "", topic.Property("OWNER"))
+
+	topic = main.childrens[1]
+	assert.Equal(t, "topic 2", topic.text)
+	assert.Equal(t, "", topic.Todo())
+	assert.Equal(t, []string{"idea"}, topic.Tags())
+}
+
+func TestOrgTodoKeywords(t *testing.T) {
+	tests := []struct {
+		line string
+		want map[string]bool
+	}{
+		{" TODO NEXT | DONE CANCELED", map[string]bool{"TODO": false, "NEXT": false, "DONE": true, "CANCELED": true}},
+		{" TODO(t) WAIT(w@/!) | DONE(d!)", map[string]bool{"TODO": false, "WAIT": false, "DONE": true}},
+		{" TODO FEEDBACK VERIFY DONE", map[string]bool{"TODO": false, "FEEDBACK": false, "VERIFY": false, "DONE": true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			assert.Equal(t, tt.want, orgTodoKeywords(tt.line))
+		})
+	}
+}
+
+func TestParseOrgCustomKeywords(t *testing.T) {
+	test := []string{
+		"#+TODO: NEXT WAITING | FIXED",
+		"* NEXT main idea",
+		"** TODO topic",
+		"** FIXED topic",
+	}
+
+	got, err := ParseLines(test, UseSyntax(OrgSyntax))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := got.childrens[0]
+	assert.Equal(t, "NEXT", main.Todo())
+	assert.Equal(t, "main idea", main.text)
+	assert.Equal(t, "", main.childrens[0].Todo())
+	assert.Equal(t, "TODO topic", main.childrens[0].text)
+	assert.Equal(t, "FIXED", main.childrens[1].Todo())
+	assert.True(t, main.childrens[1].Done())
+}
@@ -0,0 +1,89 @@
+package palette
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"strings"
+)
+
+// ByLevel returns a function that supplies
+// the color (hex code) for each depth level.
+// Past the last color, the levels cycle through
+// the palette again (starting from level one).
+func ByLevel() func(lvl int) string {
+	palette := []string{
+		"#264653",
+		"#2A9D8F",
+		"#E9C46A",
+		"#E76F51",
+		"#FFCDB2",
+		"#B5838D",
+		"#6D6875",
+	}
+
+	return func(lvl int) string {
+		if lvl < 0 {
+			lvl = 0
+		}
+		if lvl >= len(palette) {
+			lvl = (lvl-1)%(len(palette)-1) + 1
+		}
+		return palette[lvl]
+	}
+}
+
+// ByKey returns a function that supplies the color (hex code)
+// for a key (i.e. a tag): the same key always gets the same color.
+func ByKey() func(key string) string {
+	palette := []string{
+		"#E76F51", "#2A9D8F", "#F4A261", "#264653",
+		"#B5838D", "#E9C46A", "#6D6875", "#8AB17D",
+	}
+
+	return func(key string) string {
+		h := fnv.New32a()
+		h.Write([]byte(strings.ToLower(key)))
+		return palette[h.Sum32()%uint32(len(palette))]
+	}
+}
+
+// HSL returns the color (hex code) with the given hue
+// (in degrees), saturation and lightness (from 0 to 1).
+func HSL(h, s, l float64) string {
+	h = math.Mod(math.Mod(h, 360)+360, 360) / 360
+	s = math.Max(0, math.Min(1, s))
+	l = math.Max(0, math.Min(1, l))
+
+	q := l * (1 + s)
+	if l >= 0.5 {
+		q = l + s - l*s
+	}
+	p := 2*l - q
+
+	r := hueToRGB(p, q, h+1.0/3)
+	g := hueToRGB(p, q, h)
+	b := hueToRGB(p, q, h-1.0/3)
+
+	return fmt.Sprintf("#%02X%02X%02X",
+		int(math.Round(r*255)), int(math.Round(g*255)), int(math.Round(b*255)))
+}
+
+func hueToRGB(p, q, t float64) float64 {
+	if t < 0 {
+		t++
+	}
+	if t > 1 {
+		t--
+	}
+
+	switch {
+	case t < 1.0/6:
+		return p + (q-p)*6*t
+	case t < 1.0/2:
+		return q
+	case t < 2.0/3:
+		return p + (q-p)*(2.0/3-t)*6
+	}
+	return p
+}
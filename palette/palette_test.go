@@ -0,0 +1,45 @@
+package palette
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHSL(t *testing.T) {
+	tests := []struct {
+		h, s, l float64
+		want    string
+	}{
+		{0, 1, 0.5, "#FF0000"},
+		{120, 1, 0.5, "#00FF00"},
+		{240, 1, 0.5, "#0000FF"},
+		{-120, 1, 0.5, "#0000FF"},
+		{0, 0, 1, "#FFFFFF"},
+		{0, 0, 0, "#000000"},
+		{173, 0.58, 0.39, "#2A9D90"},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%v_%v_%v", tt.h, tt.s, tt.l), func(t *testing.T) {
+			assert.Equal(t, tt.want, HSL(tt.h, tt.s, tt.l))
+		})
+	}
+}
+
+func TestByLevel(t *testing.T) {
+	byLevel := ByLevel()
+	assert.Equal(t, "#264653", byLevel(0))
+	assert.Equal(t, "#2A9D8F", byLevel(1))
+	assert.Equal(t, "#6D6875", byLevel(6))
+	assert.Equal(t, byLevel(1), byLevel(7))
+	assert.Equal(t, byLevel(6), byLevel(12))
+	assert.Equal(t, byLevel(1), byLevel(13))
+}
+
+func TestByKey(t *testing.T) {
+	byKey := ByKey()
+	assert.Equal(t, byKey("backend"), byKey("Backend"))
+	assert.Regexp(t, `^#[0-9A-F]{6}$`, byKey("q3"))
+}
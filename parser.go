@@ -1,141 +1,369 @@
 package crumbs
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
-	"path/filepath"
-	"regexp"
+	"io"
 	"strings"
-
-	"github.com/teris-io/shortid"
+	"unicode/utf8"
 )
 
 // ParseLines parses a slice of text lines and builds the tree.
-func ParseLines(lines []string, imagesPath, imagesSuffix string) (*Entry, error) {
-	mkID := idGenerator()
-	checkIcon := lookForIcon(imagesPath, imagesSuffix)
-
-	// generate a short id for the root node
-	rootID, err := mkID()
+//
+// In strict mode all the diagnostics are collected and returned
+// as ParseErrors; otherwise offending lines are handled as usual
+// (skipped or taken as they are) and reported to the Warn function.
+func ParseLines(lines []string, opts ...ParseOption) (*Entry, error) {
+	p, err := newParser(newParseOptions(opts...))
 	if err != nil {
 		return nil, err
 	}
 
-	// create the root node
-	root := newEmptyNote(rootID)
-
-	node := root
-	nodeDepth := 0
-	for _, el := range lines {
-		// skip empty lines
-		if strings.TrimSpace(el) == "" {
-			continue
+	for i, el := range lines {
+		if err := p.parseLine(i+1, el); err != nil {
+			return nil, err
 		}
+	}
 
-		// count depth
-		childDepth := depth(el)
+	return p.result()
+}
 
-		// case: no leading 'stars' (skip line)
-		if childDepth == 0 {
-			continue
-		}
+// Parse reads the text lines one by one from r and builds the tree.
+//
+// Unlike ParseLines the whole input is never held in memory,
+// so there is no limit on its size, unless one is set
+// with the MaxSize option: in this case ErrTooLarge is returned
+// as soon as the limit is exceeded.
+func Parse(r io.Reader, opts ...ParseOption) (*Entry, error) {
+	cfg := newParseOptions(opts...)
 
-		// trim leading 'stars', then the spaces
-		text := el[childDepth:]
-		text = strings.TrimSpace(text)
+	p, err := newParser(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-		// create the child
-		childID, err := mkID()
-		if err != nil {
+	sc := bufio.NewScanner(LimitReader(r, cfg.MaxSize))
+	sc.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		if err := p.parseLine(lineNo, sc.Text()); err != nil {
 			return nil, err
 		}
-		child := newNote(childID, childDepth, text)
-		// check if has an icon
-		checkIcon(child)
-
-		// case: the current 'node' is the parent
-		if childDepth > nodeDepth {
-			// update tree
-			child.parent = node
-			node.childrens = append(node.childrens, child)
-
-			// update loop state
-			node = child
-			nodeDepth++
-
-			// case: the current 'node' is not the parent of our child
-		} else if childDepth <= nodeDepth {
-			// adjust 'node' until it's correct
-			for childDepth <= nodeDepth {
-				node = node.parent
-				nodeDepth--
-			}
-
-			// update tree
-			child.parent = node
-			node.childrens = append(node.childrens, child)
-
-			// update loop state
-			node = child
-			nodeDepth++
-		}
 	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	return p.result()
+}
+
+// parser holds the state of the tree under construction.
+type parser struct {
+	// parseLine adds the entry defined by a text line
+	// to the tree, according to the syntax.
+	parseLine func(lineNo int, el string) error
 
-	return root, nil
+	opts      ParseOptions
+	mkID      func(*Entry) (string, error)
+	checkIcon func(*Entry) error
+	errs      ParseErrors
+	root      *Entry
+	node      *Entry
+	nodeDepth int
+	// cont is true if the last line ends with '\'
+	// (the next one is a note line)
+	cont bool
+}
+
+// newParser creates a new parser and its root node.
+func newParser(opts ParseOptions) (*parser, error) {
+	if opts.CheckIcons {
+		opts.Icons = checkedIcons(opts.Icons)
+	}
+
+	p := &parser{
+		opts:      opts,
+		mkID:      idGenerator(opts.IDs),
+		checkIcon: lookForIcon(opts.Icons),
+	}
+
+	// create the root node
+	p.root = newEmptyNote()
+
+	// generate the id for the root node
+	var err error
+	if p.root.id, err = p.mkID(p.root); err != nil {
+		return nil, err
+	}
+
+	p.node = p.root
+
+	switch opts.Syntax {
+	case MarkdownSyntax:
+		p.parseLine = markdownLines(p)
+	case IndentSyntax:
+		p.parseLine = indentedLines(p)
+	case OrgSyntax:
+		p.parseLine = orgLines(p)
+	default:
+		p.parseLine = p.parseStars
+	}
+
+	return p, nil
+}
+
+// report records (strict mode) or notifies a diagnostic.
+func (p *parser) report(line, col int, kind ErrorKind, format string, args ...interface{}) {
+	e := &ParseError{
+		Line:   line,
+		Column: col,
+		Kind:   kind,
+		Msg:    fmt.Sprintf(format, args...),
+	}
+
+	if p.opts.Strict {
+		p.errs = append(p.errs, e)
+	} else if p.opts.Warn != nil {
+		p.opts.Warn(e)
+	}
+}
+
+// fail records a diagnostic that makes the parse
+// fail, whether or not the strict mode is on.
+func (p *parser) fail(line, col int, kind ErrorKind, format string, args ...interface{}) {
+	p.errs = append(p.errs, &ParseError{
+		Line:   line,
+		Column: col,
+		Kind:   kind,
+		Msg:    fmt.Sprintf(format, args...),
+	})
+}
+
+// parseStars adds the entry defined by the
+// asterisk-indented text line to the tree.
+func (p *parser) parseStars(lineNo int, el string) error {
+	// skip empty lines
+	if strings.TrimSpace(el) == "" {
+		p.cont = false
+		return nil
+	}
+
+	// count depth
+	childDepth := depth(el, p.opts.Bullet)
+
+	// case: continuation line (indented or following a '\')
+	indented := childDepth == 0 && strings.TrimLeft(el, " \t") != el
+	if p.node != p.root && (p.cont || indented) {
+		p.addNote(el)
+		return nil
+	}
+
+	// case: no leading 'stars' (skip line)
+	if childDepth == 0 {
+		p.report(lineNo, 1, OrphanText, "orphan text: line has no leading %q", p.opts.Bullet)
+		return nil
+	}
+
+	// trim leading 'stars', then the spaces
+	text := el[childDepth*utf8.RuneLen(p.opts.Bullet):]
+	col := childDepth + len(text) - len(strings.TrimLeft(text, " \t")) + 1
+
+	text = strings.TrimSpace(text)
+	text, p.cont = strings.TrimSuffix(text, `\`), strings.HasSuffix(text, `\`)
+
+	_, err := p.add(lineNo, col, childDepth, strings.TrimSpace(text))
+	return err
+}
+
+// addNote appends a line to the note of the current
+// entry; a trailing '\' continues the note on the next line.
+func (p *parser) addNote(line string) {
+	line = strings.TrimSpace(line)
+	line, p.cont = strings.TrimSuffix(line, `\`), strings.HasSuffix(line, `\`)
+
+	appendNote(p.node, strings.TrimSpace(line))
+}
+
+// add creates a new entry with the given level and
+// text (found at line and column), looks for the inline
+// syntax (anchor, links, attributes and tags) in the
+// text and attaches the entry to the tree.
+func (p *parser) add(lineNo, col, childDepth int, text string) (*Entry, error) {
+	child := p.newEntry(lineNo, col, childDepth, text)
+	// check if has an anchor or some links
+	lookForLinks(child)
+	// check if has some attributes
+	lookForAttrs(child)
+	// check if has some tags
+	lookForTags(child)
+
+	return p.attach(child)
+}
+
+// newEntry creates a new entry with the given level and
+// text (found at line and column), resolving its icon marker.
+func (p *parser) newEntry(lineNo, col, childDepth int, text string) *Entry {
+	// compare with the level of the current 'node' (root counts as zero)
+	lvl := p.node.level
+	if lvl < 0 {
+		lvl = 0
+	}
+	if childDepth > lvl+1 {
+		p.report(lineNo, 1, LevelJump, "level jump: from level %d to level %d", lvl, childDepth)
+	}
+
+	if text == "" {
+		p.report(lineNo, col, EmptyHeading, "empty heading: entry has no text")
+	}
+
+	// create the child
+	child := newNote(childDepth, text)
+	child.line = lineNo
+	// check if has an icon
+	if err := p.checkIcon(child); err != nil {
+		kind := iconErrorKind(err)
+		p.report(lineNo, col, kind, "%s: %s", kind, err.Error())
+	}
+
+	return child
+}
+
+// attach appends the entry to the tree, as a child of the
+// last entry with a lower level, and generates its id.
+func (p *parser) attach(child *Entry) (*Entry, error) {
+	childDepth := child.level
+	// case: the current 'node' is not the parent of our child
+	// adjust 'node' until it's correct
+	for childDepth <= p.nodeDepth {
+		p.node = p.node.parent
+		p.nodeDepth--
+	}
+
+	// update tree
+	child.parent = p.node
+	p.node.childrens = append(p.node.childrens, child)
+
+	// generate the id (now that the child is attached)
+	var err error
+	if child.id, err = p.mkID(child); err != nil {
+		return nil, err
+	}
+
+	// update loop state
+	p.node = child
+	p.nodeDepth++
+
+	return child, nil
+}
+
+// setIcon resolves the icon name and assigns it to the entry.
+func (p *parser) setIcon(e *Entry, name string) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return
+	}
+
+	icon, err := p.opts.Icons.Resolve(name)
+	if err != nil {
+		kind := iconErrorKind(err)
+		p.report(e.line, 1, kind, "%s: %s", kind, err.Error())
+		return
+	}
+
+	e.icon = icon
+}
+
+// result returns the tree or the errors found in strict mode.
+func (p *parser) result() (*Entry, error) {
+	resolveLinks(p.root, func(e *Entry, kind ErrorKind, msg string) {
+		p.report(e.line, 1, kind, "%s", msg)
+	})
+
+	if len(p.errs) > 0 {
+		return nil, p.errs
+	}
+
+	return p.root, nil
 }
 
 // depth space-counting helper (probably done in a dumb way, dunno)
-func depth(line string) int {
+func depth(line string, marker rune) int {
 	i := 0
-	for line[i] == '*' {
+	for _, r := range line {
+		if r != marker {
+			break
+		}
 		i++
 	}
 
 	return i
 }
 
+// appendNote appends a line to the entry note.
+func appendNote(e *Entry, line string) {
+	if e.note != "" {
+		e.note += "\n"
+	}
+	e.note += line
+}
+
 // newNote creates a new note element
-func newNote(id string, lvl int, txt string) *Entry {
+func newNote(lvl int, txt string) *Entry {
 	f := new(Entry)
-	f.id = id
 	f.text = txt
 	f.level = lvl
 	return f
 }
 
-// newNote creates a new note element
-func newEmptyNote(id string) *Entry {
+// newEmptyNote creates a new (root) note element
+func newEmptyNote() *Entry {
 	f := new(Entry)
-	f.id = id
 	f.level = -1
 	return f
 }
 
-// idGenerator generates a new short id at each invocation.
-func idGenerator() func() (string, error) {
-	sid, err := shortid.New(1, shortid.DefaultABC, 2342)
-	if err != nil {
-		return func() (string, error) {
-			return "", err
-		}
-	}
+// maxLineSize is the maximum length of a single text line.
+const maxLineSize = 1024 * 1024
+
+// ErrTooLarge is returned by Parse when the input
+// exceeds the MaxSize limit.
+var ErrTooLarge = errors.New("input exceeds the size limit")
 
-	return func() (string, error) {
-		return sid.Generate()
+// LimitReader returns a reader that reads from r until n bytes
+// are read, then fails with ErrTooLarge (zero means no limit).
+func LimitReader(r io.Reader, n int64) io.Reader {
+	if n <= 0 {
+		return r
 	}
+	return &sizeLimitReader{r: r, n: n, limit: n}
+}
+
+// sizeLimitReader reads from r until n bytes are left,
+// then fails with ErrTooLarge.
+type sizeLimitReader struct {
+	r     io.Reader
+	n     int64
+	limit int64
 }
 
-func lookForIcon(imagesPath, imagesSuffix string) func(note *Entry) {
-	re := regexp.MustCompile(`^\[{2}(.*?)\]{2}`)
+func (l *sizeLimitReader) Read(p []byte) (int, error) {
+	if l.n < 0 {
+		return 0, fmt.Errorf("%w (%d bytes)", ErrTooLarge, l.limit)
+	}
 
-	return func(note *Entry) {
-		str := note.text
-		res := re.FindStringSubmatch(str)
-		if len(res) > 0 {
-			note.icon = filepath.Join(imagesPath, strings.TrimSpace(res[1]))
-			if len(imagesSuffix) > 0 {
-				note.icon = fmt.Sprintf("%s.%s", note.icon, imagesSuffix)
-			}
-			note.text = re.ReplaceAllString(str, "")
-		}
+	// read one more byte than allowed to detect the overflow
+	if int64(len(p)) > l.n+1 {
+		p = p[:l.n+1]
 	}
+
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	if l.n < 0 {
+		return 0, fmt.Errorf("%w (%d bytes)", ErrTooLarge, l.limit)
+	}
+
+	return n, err
 }
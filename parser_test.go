@@ -1,6 +1,8 @@
 package crumbs
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -17,7 +19,7 @@ func TestParseLines(t *testing.T) {
 ** topic 2
 *** sub topic 2 1
 `
-	got, err := ParseLines(strings.SplitAfter(test, "\n"), "")
+	got, err := ParseLines(strings.SplitAfter(test, "\n"))
 	if err != nil {
 		t.Error(err)
 	}
@@ -53,7 +55,7 @@ func TestLookForIcon(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		fn := lookForIcon(tt.imagespath)
+		fn := lookForIcon(DirIcons(tt.imagespath, ""))
 		fn(&tt.entry)
 
 		t.Run(tt.imagespath, func(t *testing.T) {
@@ -74,12 +76,168 @@ func TestDepth(t *testing.T) {
 		{"** topic 1 LV.2", 2},
 		{"*** sub topic LV.3", 3},
 		{"******* LV.7", 7},
+		{"***", 3},
 	}
 
 	for _, tt := range tests {
 
 		t.Run(tt.line, func(t *testing.T) {
-			assert.Equal(t, depth(tt.line), tt.want)
+			assert.Equal(t, depth(tt.line, '*'), tt.want)
 		})
 	}
 }
+
+func TestParseLinesDiagnostics(t *testing.T) {
+	test := `* main idea
+orphan text
+**** too deep
+** [[bulb.png topic
+***
+** [[ ]] no name
+`
+	want := []struct {
+		line, col int
+		kind      ErrorKind
+	}{
+		{2, 1, OrphanText},
+		{3, 1, LevelJump},
+		{4, 4, MalformedIcon},
+		{5, 4, EmptyHeading},
+		{6, 4, MalformedIcon},
+	}
+
+	lines := strings.SplitAfter(test, "\n")
+
+	var warns []*ParseError
+	got, err := ParseLines(lines, Warn(func(e *ParseError) {
+		warns = append(warns, e)
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(got.childrens))
+
+	_, err = ParseLines(lines, Strict(true))
+	errs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("got [%v] want ParseErrors", err)
+	}
+
+	assert.Equal(t, len(want), len(warns))
+	assert.Equal(t, len(want), len(errs))
+	for i, tt := range want {
+		assert.Equal(t, tt.line, errs[i].Line)
+		assert.Equal(t, tt.col, errs[i].Column)
+		assert.Equal(t, tt.kind, errs[i].Kind)
+		assert.Equal(t, errs[i], warns[i])
+	}
+}
+
+func TestParse(t *testing.T) {
+	test := `* main idea
+** [[bulb]] topic 1
+*** sub topic 1 1
+** topic 2
+`
+	got, err := Parse(strings.NewReader(test), ImagesPath("icons"), ImagesSuffix("png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want, err := ParseLines(strings.SplitAfter(test, "\n"),
+		ImagesPath("icons"), ImagesSuffix("png"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, want, got)
+	assert.Equal(t, "icons/bulb.png", got.childrens[0].childrens[0].icon)
+}
+
+func TestParseNotes(t *testing.T) {
+	test := "* main idea\n" +
+		"  first note line\n" +
+		"\tsecond note line\n" +
+		"** topic 1 \\\n" +
+		"continued note \\\n" +
+		"on more lines\n" +
+		"\n" +
+		"** topic 2\n"
+
+	got, err := Parse(strings.NewReader(test), Strict(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	main := got.childrens[0]
+	assert.Equal(t, "main idea", main.text)
+	assert.Equal(t, "first note line\nsecond note line", main.Note())
+	assert.Equal(t, 2, len(main.childrens))
+	assert.Equal(t, "topic 1", main.childrens[0].text)
+	assert.Equal(t, "continued note\non more lines", main.childrens[0].Note())
+	assert.Equal(t, "", main.childrens[1].Note())
+
+	// without an entry to attach to, it's orphan text
+	_, err = ParseLines([]string{"  a note", "* main idea"}, Strict(true))
+	errs, ok := err.(ParseErrors)
+	if !ok {
+		t.Fatalf("got [%v] want ParseErrors", err)
+	}
+	assert.Equal(t, OrphanText, errs[0].Kind)
+}
+
+func TestParseMaxSize(t *testing.T) {
+	test := "* main idea\n** topic 1\n"
+
+	tests := []struct {
+		limit int64
+		err   error
+	}{
+		{0, nil},
+		{int64(len(test)), nil},
+		{int64(len(test)) - 1, ErrTooLarge},
+		{5, ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("limit_%d", tt.limit), func(t *testing.T) {
+			_, err := Parse(strings.NewReader(test), MaxSize(tt.limit))
+			if tt.err == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, tt.err), "got [%v] want [%v]", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestParseOptions(t *testing.T) {
+	test := `- main idea
+-- [[bulb]] topic 1
+--- [[nope]] sub topic 1 1
+-- topic 2
+`
+	var warns []*ParseError
+	got, err := ParseLines(strings.SplitAfter(test, "\n"),
+		Bullet('-'),
+		Icons(IconResolverFunc(func(name string) (string, error) {
+			if name != "bulb" {
+				return "", fmt.Errorf("icon '%s' not found", name)
+			}
+			return "/icons/bulb.svg", nil
+		})),
+		Warn(func(e *ParseError) {
+			warns = append(warns, e)
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "main idea", got.childrens[0].text)
+	assert.Equal(t, 2, len(got.childrens[0].childrens))
+	assert.Equal(t, "/icons/bulb.svg", got.childrens[0].childrens[0].icon)
+	assert.Equal(t, "", got.childrens[0].childrens[0].childrens[0].icon)
+
+	assert.Equal(t, 1, len(warns))
+	assert.Equal(t, UnknownIcon, warns[0].Kind)
+	assert.Equal(t, 3, warns[0].Line)
+}
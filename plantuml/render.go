@@ -0,0 +1,94 @@
+package plantuml
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/palette"
+)
+
+// RenderConfig defines some render parameters.
+type RenderConfig struct {
+	// BalancedSides splits the first level branches
+	// between the right and the left side of the main idea.
+	BalancedSides bool
+	// NoColors disables the per level node colors.
+	NoColors bool
+}
+
+// Render translates the mind note tree to a
+// PlantUML mindmap definition.
+func Render(wr io.Writer, note *crumbs.Entry, cfg RenderConfig) error {
+	tintFor := palette.ByLevel()
+	if cfg.NoColors {
+		tintFor = func(int) string { return "" }
+	}
+
+	bw := bufio.NewWriter(wr)
+	fmt.Fprintln(bw, "@startmindmap")
+
+	for _, el := range note.Root().Childrens() {
+		renderNode(bw, el, "+", 1, tintFor)
+
+		right, left := el.Childrens(), []*crumbs.Entry{}
+		if cfg.BalancedSides {
+			right, left = crumbs.Balance(right)
+		}
+
+		for _, child := range right {
+			renderTree(bw, child, "+", 2, tintFor)
+		}
+		for _, child := range left {
+			renderTree(bw, child, "-", 2, tintFor)
+		}
+	}
+
+	fmt.Fprintln(bw, "@endmindmap")
+
+	return bw.Flush()
+}
+
+// render a tree node (the node, and its children)
+// on the side defined by the bullet ('+' right, '-' left)
+func renderTree(wr io.Writer, el *crumbs.Entry, bullet string, depth int, tintFor func(int) string) {
+	renderNode(wr, el, bullet, depth, tintFor)
+
+	for _, child := range el.Childrens() {
+		renderTree(wr, child, bullet, depth+1, tintFor)
+	}
+}
+
+// renderNode writes a single node line.
+func renderNode(wr io.Writer, el *crumbs.Entry, bullet string, depth int, tintFor func(int) string) {
+	fmt.Fprint(wr, strings.Repeat(bullet, depth))
+	if color := tintFor(depth); color != "" {
+		fmt.Fprintf(wr, "[%s]", color)
+	}
+	fmt.Fprint(wr, " ")
+
+	if icon := el.Icon(); crumbs.IsEmoji(icon) {
+		fmt.Fprintf(wr, "%s ", icon)
+	} else if len(icon) > 0 {
+		if crumbs.IsBundledIcon(icon) {
+			// there is no file to reference
+			icon, _ = crumbs.IconDataURI(icon)
+		}
+		fmt.Fprintf(wr, "<img:%s> ", icon)
+	}
+
+	fmt.Fprintln(wr, label(el.Text()))
+}
+
+var reLineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+
+// label turns the entry text into a node label,
+// the creole syntax understands the other HTML tags.
+func label(str string) string {
+	res := strings.TrimSpace(str)
+	res = strings.ReplaceAll(res, "\n", " ")
+	return reLineBreak.ReplaceAllString(res, `\n`)
+}
@@ -0,0 +1,99 @@
+package plantuml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+const sample = `
+* [[png/bulb.png]] main idea
+** topic 1
+*** sub <b>topic</b><br/>on two lines
+*** sub topic
+** topic 2
+*** sub topic
+`
+
+func TestRender(t *testing.T) {
+	want := `@startmindmap
++[#2A9D8F] <img:png/bulb.png> main idea
+++[#E9C46A] topic 1
++++[#E76F51] sub <b>topic</b>\non two lines
++++[#E76F51] sub topic
+++[#E9C46A] topic 2
++++[#E76F51] sub topic
+@endmindmap
+`
+
+	note, err := crumbs.ParseLines(strings.SplitAfter(sample, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, note, RenderConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, buf.String())
+}
+
+func TestRenderBalancedSides(t *testing.T) {
+	want := `@startmindmap
++ <img:png/bulb.png> main idea
+++ topic 1
++++ sub <b>topic</b>\non two lines
++++ sub topic
+-- topic 2
+--- sub topic
+@endmindmap
+`
+
+	note, err := crumbs.ParseLines(strings.SplitAfter(sample, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, note, RenderConfig{BalancedSides: true, NoColors: true}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, want, buf.String())
+}
+
+func TestRenderDeepTree(t *testing.T) {
+	var lines []string
+	for i := 1; i <= 8; i++ {
+		lines = append(lines, strings.Repeat("*", i)+" "+string(rune('a'+i-1)))
+	}
+
+	note, err := crumbs.ParseLines(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, note, RenderConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	assert.NotContains(t, buf.String(), "#000000")
+	assert.Contains(t, buf.String(), "\n++++++[#6D6875] f\n")
+	assert.Contains(t, buf.String(), "\n+++++++[#2A9D8F] g\n")
+	assert.Contains(t, buf.String(), "\n++++++++[#E9C46A] h\n")
+}
+
+func TestRenderBundledIcon(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{"* [[:bulb:]] idea"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, note, RenderConfig{NoColors: true}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, buf.String(), "\n+ <img:data:image/svg+xml;base64,")
+}
@@ -0,0 +1,291 @@
+package svg
+
+import (
+	"math"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/text"
+)
+
+const (
+	// charWidth is the width of a monospaced character (in em)
+	charWidth = 0.6
+	// lineHeight is the height of a text line (in em)
+	lineHeight = 1.3
+	// iconSize is the width and the height of the icons
+	iconSize = 48.0
+	// nodePadding is the space around the node contents
+	nodePadding = 8.0
+	// nodeGap is the minimum space between two adjacent nodes
+	nodeGap = 24.0
+	// levelGap is the space between two levels
+	levelGap = 80.0
+)
+
+// box is a laid out tree node.
+type box struct {
+	entry    *crumbs.Entry
+	lines    []string
+	fontSize float64
+	w, h     float64 // size
+	x, y     float64 // center
+	rel      float64 // breadth offset from the parent center
+	children []*box
+}
+
+// span is the breadth extent of a subtree at a given depth.
+type span struct {
+	lo, hi float64
+}
+
+// layout is a tidy tree layout.
+type layout struct {
+	vertical bool
+	radial   bool
+	wrapLim  uint
+}
+
+// place builds the boxes tree for the note
+// children and computes the position of each box.
+// It returns the (virtual) root box and the drawing size.
+func (l *layout) place(note *crumbs.Entry) (*box, float64, float64) {
+	root := &box{entry: note}
+	for _, el := range note.Childrens() {
+		root.children = append(root.children, l.measure(el))
+	}
+
+	if len(root.children) == 0 {
+		return root, 0, 0
+	}
+
+	if l.radial {
+		// every main idea in the center of its branches,
+		// one below the other
+		offset := 0.0
+		for _, el := range root.children {
+			right, left := balance(el.children)
+			l.placeSide(el, right, 1)
+			l.placeSide(el, left, -1)
+
+			_, minY, _, maxY := bounds(el)
+			translate(el, 0, offset-minY)
+			offset += maxY - minY + nodeGap
+		}
+	} else {
+		l.placeSide(root, root.children, 1)
+	}
+
+	minX, minY, maxX, maxY := math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)
+	for _, el := range root.children {
+		x0, y0, x1, y1 := bounds(el)
+		minX, minY = math.Min(minX, x0), math.Min(minY, y0)
+		maxX, maxY = math.Max(maxX, x1), math.Max(maxY, y1)
+	}
+
+	// move everything at the origin
+	for _, el := range root.children {
+		translate(el, -minX, -minY)
+	}
+
+	return root, maxX - minX, maxY - minY
+}
+
+// placeSide computes the position of the children boxes
+// (and their descendants) of the already placed parent.
+// The levels grow in the dir direction (1 or -1) along the depth axis.
+func (l *layout) placeSide(parent *box, children []*box, dir float64) {
+	if len(children) == 0 {
+		return
+	}
+
+	// depth axis: every level is as large as its largest node
+	var sizes []float64
+	l.levelSizes(children, 0, &sizes)
+
+	pos, edge := parent.x, parent.w/2
+	if l.vertical {
+		pos, edge = parent.y, parent.h/2
+	}
+
+	centers := make([]float64, len(sizes))
+	start := pos + dir*(edge+levelGap)
+	for i, size := range sizes {
+		centers[i] = start + dir*size/2
+		start += dir * (size + levelGap)
+	}
+
+	// breadth axis: contour based tidy tree
+	l.arrange(&box{w: parent.w, h: parent.h, children: children})
+
+	var visit func(el *box, lvl int, pos float64)
+	visit = func(el *box, lvl int, pos float64) {
+		if l.vertical {
+			el.x, el.y = pos, centers[lvl]
+		} else {
+			el.x, el.y = centers[lvl], pos
+		}
+
+		for _, c := range el.children {
+			visit(c, lvl+1, pos+c.rel)
+		}
+	}
+
+	pos = parent.y
+	if l.vertical {
+		pos = parent.x
+	}
+	for _, c := range children {
+		visit(c, 0, pos+c.rel)
+	}
+}
+
+// balance splits the boxes in two groups, as crumbs.Balance
+// does with their entries (the boxes mirror the entry children).
+func balance(boxes []*box) (first, second []*box) {
+	entries := make([]*crumbs.Entry, len(boxes))
+	for i, el := range boxes {
+		entries[i] = el.entry
+	}
+
+	head, _ := crumbs.Balance(entries)
+	return boxes[:len(head)], boxes[len(head):]
+}
+
+// bounds returns the bounding box of the subtree.
+func bounds(el *box) (minX, minY, maxX, maxY float64) {
+	minX, minY = el.x-el.w/2, el.y-el.h/2
+	maxX, maxY = el.x+el.w/2, el.y+el.h/2
+
+	for _, c := range el.children {
+		x0, y0, x1, y1 := bounds(c)
+		minX, minY = math.Min(minX, x0), math.Min(minY, y0)
+		maxX, maxY = math.Max(maxX, x1), math.Max(maxY, y1)
+	}
+
+	return
+}
+
+// translate moves the subtree by dx, dy.
+func translate(el *box, dx, dy float64) {
+	el.x, el.y = el.x+dx, el.y+dy
+	for _, c := range el.children {
+		translate(c, dx, dy)
+	}
+}
+
+// measure creates the box (and the children boxes)
+// for the note, computing its size.
+func (l *layout) measure(note *crumbs.Entry) *box {
+	res := &box{entry: note, fontSize: 12}
+	if note.Level() == 1 {
+		res.fontSize = 14
+	}
+
+	res.lines = labelLines(note.Text(), l.wrapLim)
+
+	cols := 0
+	for _, s := range res.lines {
+		if n := len([]rune(s)); n > cols {
+			cols = n
+		}
+	}
+
+	res.w = float64(cols) * charWidth * res.fontSize
+	res.h = float64(len(res.lines)) * lineHeight * res.fontSize
+	if len(note.Icon()) > 0 {
+		res.w = math.Max(res.w, iconSize)
+		res.h += iconSize
+	}
+	res.w += 2 * nodePadding
+	res.h += 2 * nodePadding
+
+	for _, el := range note.Childrens() {
+		res.children = append(res.children, l.measure(el))
+	}
+
+	return res
+}
+
+// breadth returns the box size along the breadth axis.
+func (l *layout) breadth(el *box) float64 {
+	if l.vertical {
+		return el.w
+	}
+	return el.h
+}
+
+// levelSizes collects the size of the largest box
+// (along the depth axis) for each level.
+func (l *layout) levelSizes(boxes []*box, lvl int, sizes *[]float64) {
+	for _, el := range boxes {
+		if lvl >= len(*sizes) {
+			*sizes = append(*sizes, 0)
+		}
+
+		size := el.w
+		if l.vertical {
+			size = el.h
+		}
+		(*sizes)[lvl] = math.Max((*sizes)[lvl], size)
+
+		l.levelSizes(el.children, lvl+1, sizes)
+	}
+}
+
+// arrange places the children of el (setting their
+// breadth offset) and returns the contour of the subtree,
+// one span for each depth, relative to the center of el.
+func (l *layout) arrange(el *box) []span {
+	half := l.breadth(el) / 2
+	if len(el.children) == 0 {
+		return []span{{-half, half}}
+	}
+
+	var merged []span
+	offsets := make([]float64, len(el.children))
+	for i, c := range el.children {
+		cc := l.arrange(c)
+		if i > 0 {
+			// the minimum shift that avoids overlaps at every common depth
+			offsets[i] = math.Inf(-1)
+			for k := 0; k < len(cc) && k < len(merged); k++ {
+				offsets[i] = math.Max(offsets[i], merged[k].hi+nodeGap-cc[k].lo)
+			}
+		}
+
+		for k, s := range cc {
+			s.lo, s.hi = s.lo+offsets[i], s.hi+offsets[i]
+			if k < len(merged) {
+				merged[k].lo = math.Min(merged[k].lo, s.lo)
+				merged[k].hi = math.Max(merged[k].hi, s.hi)
+			} else {
+				merged = append(merged, s)
+			}
+		}
+	}
+
+	// center the parent on its first and last child
+	mid := (offsets[0] + offsets[len(offsets)-1]) / 2
+	for i, c := range el.children {
+		c.rel = offsets[i] - mid
+	}
+
+	res := []span{{-half, half}}
+	for _, s := range merged {
+		res = append(res, span{s.lo - mid, s.hi - mid})
+	}
+
+	return res
+}
+
+// labelLines turns the note text (that can contain
+// some HTML tags) into the wrapped label lines.
+func labelLines(str string, lim uint) []string {
+	label := text.StripTags(strings.TrimSpace(str))
+	if lim > 0 {
+		label = text.WrapString(label, lim)
+	}
+
+	return strings.Split(label, "\n")
+}
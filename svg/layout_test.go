@@ -0,0 +1,154 @@
+package svg
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+const sample = `
+* main idea
+** topic 1
+*** sub topic with a quite long text that will be wrapped
+*** sub topic
+**** sub topic
+**** sub topic
+** topic 2
+*** sub topic
+`
+
+func TestLayoutNoOverlaps(t *testing.T) {
+	note, err := crumbs.ParseLines(strings.SplitAfter(sample, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, vertical := range []bool{false, true} {
+		lay := &layout{vertical: vertical, wrapLim: 20}
+		root, w, h := lay.place(note)
+
+		var all []*box
+		var collect func(el *box)
+		collect = func(el *box) {
+			all = append(all, el)
+			for _, c := range el.children {
+				collect(c)
+			}
+		}
+		for _, el := range root.children {
+			collect(el)
+		}
+
+		assert.Equal(t, 8, len(all))
+		for i, a := range all {
+			const eps = 1e-9
+			assert.True(t, a.x-a.w/2 >= -eps && a.x+a.w/2 <= w+eps)
+			assert.True(t, a.y-a.h/2 >= -eps && a.y+a.h/2 <= h+eps)
+
+			for _, b := range all[i+1:] {
+				overlapX := a.x-a.w/2 < b.x+b.w/2 && b.x-b.w/2 < a.x+a.w/2
+				overlapY := a.y-a.h/2 < b.y+b.h/2 && b.y-b.h/2 < a.y+a.h/2
+				assert.False(t, overlapX && overlapY, "'%s' overlaps '%s'", a.entry.Text(), b.entry.Text())
+			}
+		}
+	}
+}
+
+func TestLayoutParentCentered(t *testing.T) {
+	note, err := crumbs.ParseLines(strings.SplitAfter(sample, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lay := &layout{}
+	root, _, _ := lay.place(note)
+
+	main := root.children[0]
+	first, last := main.children[0], main.children[len(main.children)-1]
+	assert.InDelta(t, (first.y+last.y)/2, main.y, 0.001)
+	assert.True(t, first.x > main.x)
+	assert.Equal(t, first.x, last.x)
+}
+
+func TestLabelLines(t *testing.T) {
+	tests := []struct {
+		text string
+		lim  uint
+		want []string
+	}{
+		{"main idea", 0, []string{"main idea"}},
+		{"foo bar baz", 4, []string{"foo", "bar", "baz"}},
+		{"sub <i>topic</i><br/>next", 0, []string{"sub topic", "next"}},
+		{" topic <b>2</b> ", 28, []string{"topic 2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			assert.Equal(t, tt.want, labelLines(tt.text, tt.lim))
+		})
+	}
+}
+
+func TestLayoutRadial(t *testing.T) {
+	note, err := crumbs.ParseLines(strings.SplitAfter(sample, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lay := &layout{radial: true}
+	root, _, _ := lay.place(note)
+
+	main := root.children[0]
+	assert.Equal(t, 2, len(main.children))
+	// topic 1 on the right side, topic 2 on the left side
+	assert.True(t, main.children[0].x > main.x)
+	assert.True(t, main.children[1].x < main.x)
+	assert.True(t, main.children[1].children[0].x < main.children[1].x)
+	assert.InDelta(t, main.y, main.children[1].y, 0.001)
+}
+
+func TestBalance(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* main idea",
+		"** a", "*** a1", "*** a2", "*** a3", "*** a4",
+		"** b",
+		"** c",
+		"** d",
+		"** e",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lay := &layout{}
+	main := lay.measure(note.Childrens()[0])
+
+	first, last := balance(main.children)
+	if assert.Equal(t, 1, len(first)) && assert.Equal(t, 4, len(last)) {
+		assert.Equal(t, "a", first[0].entry.Text())
+		assert.Equal(t, "b", last[0].entry.Text())
+	}
+}
+
+func TestBalanceHeavyLast(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* main idea",
+		"** a",
+		"** b",
+		"** c",
+		"** d", "*** d1", "*** d2", "*** d3", "*** d4", "*** d5",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lay := &layout{}
+	main := lay.measure(note.Childrens()[0])
+
+	first, last := balance(main.children)
+	if assert.Equal(t, 3, len(first)) && assert.Equal(t, 1, len(last)) {
+		assert.Equal(t, "d", last[0].entry.Text())
+	}
+}
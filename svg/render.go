@@ -0,0 +1,167 @@
+package svg
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/lucasepe/crumbs/palette"
+)
+
+// margin is the space around the drawing
+const margin = 36.0
+
+// RenderConfig defines some render parameters.
+type RenderConfig struct {
+	VerticalLayout bool
+	// RadialLayout puts the main idea in the center
+	// with its branches balanced on the left and right side.
+	RadialLayout  bool
+	WrapTextLimit uint
+	// LinkIcons references the icon files instead of
+	// inlining them as data URIs (the document is
+	// then no longer self-contained); the bundled
+	// icons are inlined anyway.
+	LinkIcons bool
+}
+
+// Render lays out the mind note tree and writes it as a
+// standalone SVG document: the icon files are inlined as data
+// URIs (unless LinkIcons is set), so they must be readable.
+func Render(wr io.Writer, note *crumbs.Entry, cfg RenderConfig) error {
+	lay := &layout{
+		vertical: cfg.VerticalLayout && !cfg.RadialLayout,
+		radial:   cfg.RadialLayout,
+		wrapLim:  cfg.WrapTextLimit,
+	}
+
+	root, w, h := lay.place(note.Root())
+	w, h = w+2*margin, h+2*margin
+
+	icons := map[string]string{}
+	if err := embedIcons(note.Root(), icons, cfg.LinkIcons); err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(wr)
+
+	fmt.Fprintln(bw, `<?xml version="1.0" encoding="UTF-8" standalone="no"?>`)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" xmlns:xlink="http://www.w3.org/1999/xlink" `+
+		`width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f">`, w, h, w, h)
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, `<g transform="translate(%.0f %.0f)" font-family="Fira Code, monospace">`, margin, margin)
+	fmt.Fprintln(bw)
+
+	tintFor := palette.ByLevel()
+	for _, el := range root.children {
+		renderEdges(bw, el, tintFor, lay.vertical)
+	}
+	for _, el := range root.children {
+		renderNodes(bw, el, icons)
+	}
+
+	fmt.Fprintln(bw, "</g>")
+	fmt.Fprintln(bw, "</svg>")
+
+	return bw.Flush()
+}
+
+// renderEdges draws the connection lines between el and its children.
+func renderEdges(wr io.Writer, el *box, tintFor func(int) string, vertical bool) {
+	for _, c := range el.children {
+		var d string
+		if vertical {
+			x1, y1 := el.x, el.y+el.h/2
+			x2, y2 := c.x, c.y-c.h/2
+			my := (y1 + y2) / 2
+			d = fmt.Sprintf("M%.1f %.1f C%.1f %.1f %.1f %.1f %.1f %.1f", x1, y1, x1, my, x2, my, x2, y2)
+		} else {
+			// the child can be on the left side (radial layout)
+			side := 1.0
+			if c.x < el.x {
+				side = -1.0
+			}
+			x1, y1 := el.x+side*el.w/2, el.y
+			x2, y2 := c.x-side*c.w/2, c.y
+			mx := (x1 + x2) / 2
+			d = fmt.Sprintf("M%.1f %.1f C%.1f %.1f %.1f %.1f %.1f %.1f", x1, y1, mx, y1, mx, y2, x2, y2)
+		}
+
+		fmt.Fprintf(wr, `<path d="%s" fill="none" stroke="%s" stroke-width="2.5" stroke-linecap="round"/>`,
+			d, tintFor(c.entry.Level()))
+		fmt.Fprintln(wr)
+
+		renderEdges(wr, c, tintFor, vertical)
+	}
+}
+
+// embedIcons maps the icon files (only the bundled
+// icons when linkFiles is set) of the entry and
+// its descendants to their data URIs.
+func embedIcons(el *crumbs.Entry, icons map[string]string, linkFiles bool) error {
+	icon := el.Icon()
+	if len(icon) > 0 && !crumbs.IsEmoji(icon) && (!linkFiles || crumbs.IsBundledIcon(icon)) {
+		if _, ok := icons[icon]; !ok {
+			uri, err := crumbs.IconDataURI(icon)
+			if err != nil {
+				return fmt.Errorf("cannot embed icon: %w", err)
+			}
+			icons[icon] = uri
+		}
+	}
+
+	for _, child := range el.Childrens() {
+		if err := embedIcons(child, icons, linkFiles); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// renderNodes draws the icon and the label of el and its
+// children; the icons found in the icons map are replaced.
+func renderNodes(wr io.Writer, el *box, icons map[string]string) {
+	top := el.y - el.h/2 + nodePadding
+
+	fmt.Fprintf(wr, `<g id="%s">`, html.EscapeString(el.entry.ID()))
+
+	if icon := el.entry.Icon(); crumbs.IsEmoji(icon) {
+		fmt.Fprintf(wr, `<text x="%.1f" y="%.1f" font-size="%.0f" text-anchor="middle" dominant-baseline="central">%s</text>`,
+			el.x, top+iconSize/2, iconSize*0.75, html.EscapeString(icon))
+		top += iconSize
+	} else if len(icon) > 0 {
+		if uri, ok := icons[icon]; ok {
+			icon = uri
+		}
+		src := html.EscapeString(icon)
+		fmt.Fprintf(wr, `<image x="%.1f" y="%.1f" width="%.0f" height="%.0f" href="%s" xlink:href="%s"/>`,
+			el.x-iconSize/2, top, iconSize, iconSize, src, src)
+		top += iconSize
+	}
+
+	weight := "normal"
+	if el.entry.Level() == 1 {
+		weight = "bold"
+	}
+
+	lh := lineHeight * el.fontSize
+	fmt.Fprintf(wr, `<text x="%.1f" y="%.1f" font-size="%.0f" font-weight="%s" text-anchor="middle">`,
+		el.x, top+lh/2, el.fontSize, weight)
+	for i, s := range el.lines {
+		dy := lh
+		if i == 0 {
+			dy = 0
+		}
+		fmt.Fprintf(wr, `<tspan x="%.1f" dy="%.1f" dominant-baseline="central">%s</tspan>`,
+			el.x, dy, html.EscapeString(strings.TrimSpace(s)))
+	}
+	fmt.Fprintln(wr, "</text></g>")
+
+	for _, c := range el.children {
+		renderNodes(wr, c, icons)
+	}
+}
@@ -0,0 +1,95 @@
+package svg
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/lucasepe/crumbs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRender(t *testing.T) {
+	test := `
+* [[../testdata/png/bulb.png]] main idea
+** topic & co
+*** sub topic
+** topic 2
+`
+	note, err := crumbs.ParseLines(strings.SplitAfter(test, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, note, RenderConfig{WrapTextLimit: 28}); err != nil {
+		t.Fatal(err)
+	}
+
+	counts := map[string]int{}
+	var texts []string
+
+	dec := xml.NewDecoder(&buf)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch el := tok.(type) {
+		case xml.StartElement:
+			counts[el.Name.Local]++
+		case xml.CharData:
+			if s := strings.TrimSpace(string(el)); s != "" {
+				texts = append(texts, s)
+			}
+		}
+	}
+
+	assert.Equal(t, 1, counts["svg"])
+	assert.Equal(t, 1, counts["image"])
+	assert.Equal(t, 3, counts["path"])
+	assert.Equal(t, 4, counts["text"])
+	assert.Equal(t, []string{"main idea", "topic & co", "sub topic", "topic 2"}, texts)
+}
+
+func TestRenderEmbedIcons(t *testing.T) {
+	note, err := crumbs.ParseLines([]string{
+		"* [[../testdata/png/bulb.png]] main idea",
+		"** [[../testdata/png/bulb.png]] topic",
+		"** [[:star:]] bundled",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := Render(&buf, note, RenderConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 4, strings.Count(buf.String(), `href="data:image/png;base64,`))
+	assert.Equal(t, 2, strings.Count(buf.String(), `href="data:image/svg+xml;base64,`))
+	assert.NotContains(t, buf.String(), "bulb.png")
+	assert.NotContains(t, buf.String(), ":star:")
+
+	buf.Reset()
+	if err := Render(&buf, note, RenderConfig{LinkIcons: true}); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 4, strings.Count(buf.String(), `href="../testdata/png/bulb.png"`))
+	assert.Equal(t, 2, strings.Count(buf.String(), `href="data:image/svg+xml;base64,`))
+
+	note, err = crumbs.ParseLines([]string{"* [[nope.png]] main idea"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = Render(&buf, note, RenderConfig{})
+	assert.EqualError(t, err, "cannot embed icon: nope.png: no such file")
+}
@@ -0,0 +1,43 @@
+package crumbs
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	reTag         = regexp.MustCompile(`(?:^|[ \t])#([\pL_][\pL\pN_-]*)`)
+	reTrailingTag = regexp.MustCompile(`(?:^|[ \t]+)#[\pL_][\pL\pN_-]*[ \t]*$`)
+)
+
+// lookForTags adds the hashtags (i.e. #backend) found in the
+// note text to the note tags. The trailing hashtags are removed
+// from the text, the others are left as they are.
+func lookForTags(note *Entry) {
+	for _, res := range reTag.FindAllStringSubmatch(note.text, -1) {
+		note.addTag(res[1])
+	}
+
+	for reTrailingTag.MatchString(note.text) {
+		note.text = reTrailingTag.ReplaceAllString(note.text, "")
+	}
+}
+
+// addTag adds the tag, if not already present.
+func (ti *Entry) addTag(tag string) {
+	if tag == "" || ti.HasTag(tag) {
+		return
+	}
+	ti.tags = append(ti.tags, tag)
+}
+
+// HasTag reports whether the node has the
+// tag (the comparison is case insensitive).
+func (ti *Entry) HasTag(tag string) bool {
+	for _, el := range ti.tags {
+		if strings.EqualFold(el, tag) {
+			return true
+		}
+	}
+	return false
+}
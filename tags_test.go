@@ -0,0 +1,49 @@
+package crumbs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookForTags(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+		tags []string
+	}{
+		{"Build API #backend #q3", "Build API", []string{"backend", "q3"}},
+		{"the #backend service", "the #backend service", []string{"backend"}},
+		{"#backend", "", []string{"backend"}},
+		{"issue #42 in C#", "issue #42 in C#", nil},
+		{"twice #a #A", "twice", []string{"a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			note := newNote(1, tt.text)
+			lookForTags(note)
+			assert.Equal(t, tt.want, note.text)
+			assert.Equal(t, tt.tags, note.Tags())
+		})
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	got, err := ParseLines([]string{
+		"* main idea",
+		"** login -> #auth #backend",
+		"** authentication {#auth} {bold} #Backend #security",
+	}, Strict(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	login, auth := got.childrens[0].childrens[0], got.childrens[0].childrens[1]
+	assert.Equal(t, "login", login.Text())
+	assert.Equal(t, []string{"backend"}, login.Tags())
+	assert.Equal(t, 1, len(login.Links()))
+	assert.Equal(t, "authentication", auth.Text())
+	assert.Equal(t, []string{"Backend", "security"}, auth.Tags())
+	assert.True(t, auth.HasTag("backend"))
+}
@@ -0,0 +1,15 @@
+package text
+
+import "regexp"
+
+var (
+	reLineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+	reTag       = regexp.MustCompile(`<[^>]*>`)
+)
+
+// StripTags removes the HTML tags from the given string,
+// turning the line breaks (<br/>) into new lines.
+func StripTags(s string) string {
+	res := reLineBreak.ReplaceAllString(s, "\n")
+	return reTag.ReplaceAllString(res, "")
+}
@@ -0,0 +1,23 @@
+package text
+
+import "testing"
+
+func TestStripTags(t *testing.T) {
+	tests := []struct {
+		html string
+		want string
+	}{
+		{"main idea", "main idea"},
+		{"topic <b>2</b>", "topic 2"},
+		{"sub <sub>topic</sub><br/>next<BR>last", "sub topic\nnext\nlast"},
+		{"a < b", "a < b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.html, func(t *testing.T) {
+			if got := StripTags(tt.html); got != tt.want {
+				t.Errorf("got [%v] want [%v]", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package text
+
+import "bytes"
+
+// LineCounter returns a function that maps the
+// (increasing) byte offsets in src to line numbers.
+func LineCounter(src []byte) func(offset int64) int {
+	line, last := 1, int64(0)
+	return func(offset int64) int {
+		if offset > int64(len(src)) {
+			offset = int64(len(src))
+		}
+		if offset > last {
+			line += bytes.Count(src[last:offset], []byte{'\n'})
+			last = offset
+		}
+		return line
+	}
+}
@@ -0,0 +1,24 @@
+package text
+
+import "testing"
+
+func TestLineCounter(t *testing.T) {
+	lines := LineCounter([]byte("one\ntwo\n\nfour"))
+
+	tests := []struct {
+		offset int64
+		want   int
+	}{
+		{0, 1},
+		{3, 1},
+		{4, 2},
+		{9, 4},
+		{100, 4},
+	}
+
+	for _, tt := range tests {
+		if got := lines(tt.offset); got != tt.want {
+			t.Errorf("offset %d: got [%v] want [%v]", tt.offset, got, tt.want)
+		}
+	}
+}
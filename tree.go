@@ -0,0 +1,209 @@
+package crumbs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// FindByID returns the entry with the given identifier
+// (this entry or one of its descendants), nil if not found.
+func (ti *Entry) FindByID(id string) *Entry {
+	var res *Entry
+	ti.Walk(func(e *Entry) {
+		if res == nil && e.id == id {
+			res = e
+		}
+	})
+	return res
+}
+
+// FindByText returns the entries (this entry and its descendants)
+// with the given text, ignoring case and surrounding whitespace.
+func (ti *Entry) FindByText(text string) []*Entry {
+	var res []*Entry
+	ti.Walk(func(e *Entry) {
+		if sameText(e.text, text) {
+			res = append(res, e)
+		}
+	})
+	return res
+}
+
+// FindByPath returns the entry at the path, a list of entries text
+// separated by '/' (i.e. 'topic 1/sub topic'): the first one can be
+// any descendant, the others are the children of the previous one.
+// It returns nil if not found.
+func (ti *Entry) FindByPath(path string) *Entry {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+
+	for _, el := range ti.FindByText(parts[0]) {
+		if res := findPath(el, parts[1:]); res != nil {
+			return res
+		}
+	}
+	return nil
+}
+
+// findPath follows the path along the entry children.
+func findPath(el *Entry, path []string) *Entry {
+	if len(path) == 0 {
+		return el
+	}
+
+	for _, c := range el.childrens {
+		if sameText(c.text, path[0]) {
+			if res := findPath(c, path[1:]); res != nil {
+				return res
+			}
+		}
+	}
+	return nil
+}
+
+// sameText compares two entry texts ignoring case and surrounding whitespace.
+func sameText(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// Subtree returns a copy of the entry (and its descendants) as the
+// only main idea of a new tree: the levels are shifted accordingly.
+// The links to the entries left out are dropped.
+func (ti *Entry) Subtree() *Entry {
+	copies := map[*Entry]*Entry{}
+
+	if ti.parent == nil {
+		res := cloneEntry(ti, nil, 0, copies)
+		relink(res, copies)
+		return res
+	}
+
+	root := newEmptyNote()
+	root.id = ti.Root().id
+	root.childrens = []*Entry{cloneEntry(ti, root, ti.level-1, copies)}
+	relink(root, copies)
+
+	return root
+}
+
+// Prune returns a copy of the tree without the entries deeper than
+// maxDepth (the main ideas are at depth 1). If more is true, each
+// entry that lost its children gets a '+N more' placeholder child
+// (with the 'placeholder' attribute), N being the number of entries left out.
+func (ti *Entry) Prune(maxDepth int, more bool) *Entry {
+	copies := map[*Entry]*Entry{}
+	root := cloneEntry(ti.Root(), nil, 0, copies)
+
+	root.Walk(func(e *Entry) {
+		if e.level < maxDepth || len(e.childrens) == 0 {
+			return
+		}
+
+		n := 0
+		for _, c := range e.childrens {
+			c.Walk(func(*Entry) { n++ })
+		}
+		e.childrens = nil
+
+		if more {
+			ph := newNote(e.level+1, fmt.Sprintf("+%d more", n))
+			ph.id = e.id + ".more"
+			ph.parent = e
+			ph.attrs = map[string]string{"placeholder": "true"}
+			e.childrens = []*Entry{ph}
+		}
+	})
+
+	relink(root, copies)
+	return root
+}
+
+// cloneEntry returns a copy of the entry and its descendants, attached
+// to parent, with the levels decreased by delta; copies maps the
+// entries to their copies.
+func cloneEntry(el, parent *Entry, delta int, copies map[*Entry]*Entry) *Entry {
+	res := *el
+	res.parent = parent
+	if res.level > 0 {
+		res.level -= delta
+	}
+	copies[el] = &res
+
+	res.childrens = make([]*Entry, 0, len(el.childrens))
+	for _, c := range el.childrens {
+		res.childrens = append(res.childrens, cloneEntry(c, &res, delta, copies))
+	}
+
+	return &res
+}
+
+// relink points the links of the copies in the tree to the
+// copies of their targets, dropping the targets not in the tree
+// (and their references).
+func relink(root *Entry, copies map[*Entry]*Entry) {
+	inTree := map[*Entry]bool{}
+	root.Walk(func(e *Entry) { inTree[e] = true })
+
+	root.Walk(func(e *Entry) {
+		var links []*Entry
+		var refs []string
+		for _, target := range e.links {
+			if c, ok := copies[target]; ok && inTree[c] {
+				links = append(links, c)
+				refs = append(refs, c.anchor)
+			}
+		}
+		e.links, e.refs = links, refs
+	})
+}
+
+// Walk calls fn for the entry and all its descendants (depth first).
+func (ti *Entry) Walk(fn func(*Entry)) {
+	fn(ti)
+	for _, el := range ti.childrens {
+		el.Walk(fn)
+	}
+}
+
+// Leaves counts the leaves of the entry subtree
+// (an entry without children is a leaf itself).
+func (ti *Entry) Leaves() int {
+	if len(ti.childrens) == 0 {
+		return 1
+	}
+
+	res := 0
+	for _, c := range ti.childrens {
+		res += c.Leaves()
+	}
+	return res
+}
+
+// Balance splits the entries in two groups, keeping their
+// order, so that the groups have about the same number of leaves
+// (i.e. the branches on the two sides of a main idea). On a tie
+// the first group is the larger one.
+func Balance(entries []*Entry) (first, second []*Entry) {
+	total := 0
+	for _, el := range entries {
+		total += el.Leaves()
+	}
+
+	// pick the split that minimizes the difference
+	// between the leaves of the two groups
+	idx, best, sum := 0, total, 0
+	for i, el := range entries {
+		sum += el.Leaves()
+		if diff := abs(2*sum - total); diff <= best {
+			idx, best = i+1, diff
+		}
+	}
+
+	return entries[:idx], entries[idx:]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,164 @@
+package crumbs
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const treeSample = `* main idea
+** topic 1
+*** sub topic -> #deep
+**** deep topic {#deep}
+***** deeper topic
+*** sub topic 2
+** topic 2
+*** sub topic
+`
+
+func TestFind(t *testing.T) {
+	note, err := Parse(strings.NewReader(treeSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "deep topic", note.FindByID("n1.1.1.1").Text())
+	assert.Nil(t, note.FindByID("n9"))
+
+	assert.Equal(t, 2, len(note.FindByText(" SUB TOPIC ")))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"topic 1/sub topic", "n1.1.1"},
+		{"topic 2/sub topic", "n1.2.1"},
+		{"main idea/topic 1/sub topic/deep topic", "n1.1.1.1"},
+		{"/deep topic/", "n1.1.1.1"},
+		{"sub topic/deep topic", "n1.1.1.1"},
+		{"topic 2/deep topic", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got := note.FindByPath(tt.path)
+			if tt.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.Equal(t, tt.want, got.ID())
+			}
+		})
+	}
+}
+
+func TestSubtree(t *testing.T) {
+	note, err := Parse(strings.NewReader(treeSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := note.FindByPath("topic 1").Subtree()
+
+	var sb strings.Builder
+	if err := Format(&sb, got); err != nil {
+		t.Fatal(err)
+	}
+	want := "* topic 1\n" +
+		"** sub topic -> #deep\n" +
+		"*** deep topic {#deep}\n" +
+		"**** deeper topic\n" +
+		"** sub topic 2\n"
+	assert.Equal(t, want, sb.String())
+
+	sub := got.Childrens()[0].Childrens()[0]
+	assert.Equal(t, 2, sub.Level())
+	assert.Equal(t, "n1.1.1", sub.ID())
+	assert.Equal(t, []*Entry{sub.Childrens()[0]}, sub.Links())
+
+	// the source tree is unchanged
+	assert.Equal(t, 3, note.FindByID("n1.1.1").Level())
+}
+
+func TestPrune(t *testing.T) {
+	note, err := Parse(strings.NewReader(treeSample))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sb strings.Builder
+	if err := Format(&sb, note.Prune(3, false)); err != nil {
+		t.Fatal(err)
+	}
+	want := "* main idea\n" +
+		"** topic 1\n" +
+		"*** sub topic\n" +
+		"*** sub topic 2\n" +
+		"** topic 2\n" +
+		"*** sub topic\n"
+	assert.Equal(t, want, sb.String())
+
+	got := note.Prune(2, true)
+	more := got.Childrens()[0].Childrens()[0].Childrens()
+	if assert.Equal(t, 1, len(more)) {
+		assert.Equal(t, "+4 more", more[0].Text())
+		assert.Equal(t, "n1.1.more", more[0].ID())
+		assert.Equal(t, "true", more[0].Attr("placeholder"))
+		assert.Equal(t, 3, more[0].Level())
+	}
+
+	// the source tree is unchanged
+	assert.Equal(t, 1, len(note.FindByID("n1.1.1").Childrens()))
+}
+
+func TestWalk(t *testing.T) {
+	note, err := ParseLines([]string{
+		"* main idea",
+		"** topic 1",
+		"*** sub topic",
+		"** topic 2",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	note.Childrens()[0].Walk(func(e *Entry) {
+		got = append(got, e.Text())
+	})
+	assert.Equal(t, []string{"main idea", "topic 1", "sub topic", "topic 2"}, got)
+}
+
+func TestBalance(t *testing.T) {
+	leaf := func() *Entry { return newNote(2, "leaf") }
+	tree := func(n int) *Entry {
+		res := newNote(2, "tree")
+		for i := 0; i < n; i++ {
+			res.childrens = append(res.childrens, newNote(3, "leaf"))
+		}
+		return res
+	}
+
+	tests := []struct {
+		entries     []*Entry
+		first, last int
+	}{
+		{[]*Entry{}, 0, 0},
+		{[]*Entry{leaf()}, 1, 0},
+		{[]*Entry{leaf(), leaf()}, 1, 1},
+		{[]*Entry{tree(4), leaf(), leaf(), leaf(), leaf()}, 1, 4},
+		{[]*Entry{leaf(), leaf(), tree(3), leaf()}, 2, 2},
+		{[]*Entry{leaf(), leaf(), leaf(), tree(5)}, 3, 1},
+	}
+
+	for _, tt := range tests {
+		first, last := Balance(tt.entries)
+		assert.Equal(t, tt.first, len(first))
+		assert.Equal(t, tt.last, len(last))
+	}
+
+	assert.Equal(t, 4, tree(4).Leaves())
+	assert.Equal(t, 1, leaf().Leaves())
+}